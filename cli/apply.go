@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <file>",
+	Short: "Apply a markdown file to current board",
+	Long:  "Apply a markdown file, e.g. one created by 'mdello pull', to the current board without opening an editor. Use '-' to read from stdin.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := setupOutput(cmd)
+		if err != nil {
+			return err
+		}
+
+		editedContent, err := readMarkdownFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		state, err := loadBoardState(output)
+		if err != nil {
+			return err
+		}
+
+		if editedContent == state.originalContent {
+			fmt.Fprintln(state.output.progress, "No changes made.")
+			return nil
+		}
+
+		diffResult, err := diffEditedContent(state, editedContent)
+		if err != nil || diffResult == nil {
+			return err
+		}
+
+		if len(diffResult.QuickActions) > 0 {
+			printPlan(state.output.progress, diffResult.QuickActions)
+		}
+		if applyDryRun {
+			fmt.Fprintln(state.output.progress, "\nDry run, no changes applied.")
+			return nil
+		}
+
+		if err := applyQuickActions(state, diffResult); err != nil {
+			return finishReport(state, err)
+		}
+
+		if len(diffResult.DetailedActions) > 0 {
+			fmt.Fprintf(state.output.progress, "\nSkipping %d item(s) marked for detailed editing, use 'mdello board' to edit them.\n", len(diffResult.DetailedActions))
+		}
+		fmt.Fprintln(state.output.progress, "\nBoard updated successfully!")
+		return finishReport(state, nil)
+	},
+}
+
+var applyDryRun bool
+
+func init() {
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Print the planned changes without applying them")
+	applyCmd.Flags().StringVarP(&outputFormat, "output", "o", outputText, "Format of the report of applied changes: text or json")
+}
+
+func readMarkdownFile(path string) (string, error) {
+	if path == "-" {
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("error reading stdin: %w", err)
+		}
+		return string(content), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading markdown file: %w", err)
+	}
+	return string(content), nil
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vinzmyko/mdello/config"
+	"github.com/vinzmyko/mdello/markdown"
+	"github.com/vinzmyko/mdello/trello/trellotest"
+)
+
+// newTestBoard seeds a fake trello with a board holding one card and points mdello at it through the environment.
+// With failWrites every request that changes the board fails, reading it still works
+func newTestBoard(t *testing.T, failWrites bool) {
+	t.Helper()
+
+	server := trellotest.NewServer()
+	t.Cleanup(server.Close)
+	board := server.AddBoard("Project")
+	server.AddCard(server.AddList(board.ID, "To Do").ID, "Fix login")
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parsing the server URL failed: %v", err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failWrites && r.Method != http.MethodGet {
+			http.Error(w, "invalid value", http.StatusBadRequest)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(api.Close)
+
+	t.Setenv(config.EnvHome, t.TempDir())
+	t.Setenv(config.EnvToken, "test-token")
+	t.Setenv(config.EnvBoard, board.ID)
+	t.Setenv("MDELLO_API_URL", api.URL)
+	apiKey = "test-key"
+}
+
+// runMdello runs the command line with args and returns what it wrote to stdout and stderr
+func runMdello(t *testing.T, args ...string) (stdout, stderr string, exitCode int) {
+	t.Helper()
+
+	// Flags and the resolved token are package state, reset them so every run starts from the defaults
+	outputFormat, applyDryRun, dryRun, verbose = outputText, false, false, false
+	boardFlag, configFlag, profileFlag = "", "", ""
+	resolvedToken = ""
+
+	var outBuffer, errBuffer bytes.Buffer
+	rootCmd.SetOut(&outBuffer)
+	rootCmd.SetErr(&errBuffer)
+	rootCmd.SetArgs(args)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	})
+
+	exitCode = execute()
+	return outBuffer.String(), errBuffer.String(), exitCode
+}
+
+func TestApplyJSONOutputStaysParseableWhenChangesFail(t *testing.T) {
+	newTestBoard(t, true)
+
+	pulled, _, exitCode := runMdello(t, "pull")
+	if exitCode != 0 {
+		t.Fatalf("pull exited with %d", exitCode)
+	}
+	path := filepath.Join(t.TempDir(), "board.md")
+	if err := os.WriteFile(path, []byte(strings.Replace(pulled, "Fix login", "Fix logout", 1)), 0o600); err != nil {
+		t.Fatalf("writing the edited board failed: %v", err)
+	}
+
+	stdout, stderr, exitCode := runMdello(t, "apply", "-o", "json", path)
+	if exitCode == 0 {
+		t.Error("apply exited with 0 although the change failed")
+	}
+
+	var report markdown.ApplyReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, stdout)
+	}
+	if report.Count(markdown.JournalFailed) != 1 {
+		t.Errorf("the report has %d failed change(s), want 1", report.Count(markdown.JournalFailed))
+	}
+	if !strings.Contains(stderr, "1 change(s) failed.") {
+		t.Errorf("the error is not on stderr:\n%s", stderr)
+	}
+}
@@ -1,196 +1,541 @@
 package cli
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vinzmyko/mdello/markdown"
 	"github.com/vinzmyko/mdello/markdown/diff"
 	"github.com/vinzmyko/mdello/trello"
+	"golang.org/x/term"
 )
 
+var dryRun bool
+
 var boardCmd = &cobra.Command{
 	Use:   "board",
 	Short: "Edit current board via markdown file",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cfg == nil || cfg.Token == "" || cfg.CurrentBoardID == "" {
-			fmt.Println("No valid cfg found. Please run 'mdello init'.")
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := setupOutput(cmd)
+		if err != nil {
+			return err
 		}
 
-		trelloClient, err := trello.NewTrelloClient(apiKey, cfg.Token)
+		state, err := loadBoardState(output)
 		if err != nil {
-			fmt.Printf("Error creating trello client: %v\n", err)
-			return
+			return err
 		}
 
-		currentBoard, err := cfg.GetCurrentBoard(trelloClient)
+		return finishReport(state, editBoard(state, state.originalContent))
+	},
+}
+
+func editBoard(state *boardState, editorContent string) error {
+	var diffResult *markdown.DiffResult
+	var conflicts []markdown.Conflict
+	for {
+		userContent, err := openEditorForContent(editorContent, fmt.Sprintf("mdello-%s", state.safeName()), ".md")
 		if err != nil {
-			fmt.Printf("Error could not access current board: %v", err)
-			return
+			return fmt.Errorf("Error with editor: %w", err)
 		}
+		userContent = markdown.StripDiagnostics(userContent)
 
-		safeName := strings.ReplaceAll(currentBoard.Name, " ", "~")
-		safeName = strings.ReplaceAll(safeName, "/", "~")
+		if userContent == state.originalContent {
+			fmt.Fprintln(state.output.progress, "No changes made.")
+			return nil
+		}
 
-		originalContent, boardSession, err := markdown.ToMarkdown(trelloClient, cfg, currentBoard)
-		if err != nil {
-			fmt.Printf("Converting to markdown failed: %v", err)
-			return
+		// What the user wrote is kept for reopening the editor and saving the edit, editedContent is what is applied
+		editedContent := userContent
+		diffResult, err = diffEditedContent(state, editedContent)
+		if errors.Is(err, markdown.ErrUnresolvedConflict) {
+			fmt.Fprintln(state.output.progress, err)
+			if !askReopen(state.output.progress) {
+				saveRecovery(state, userContent)
+				fmt.Fprintln(state.output.progress, "No changes applied.")
+				return nil
+			}
+			editorContent = userContent
+			continue
 		}
+		var parseErr *markdown.ParseError
+		if errors.As(err, &parseErr) {
+			parsedContent, parseErrors := markdown.SkipUnparsableLines(userContent, state.originalContent, state.session)
+			fmt.Fprintf(state.output.progress, "\nCould not read %d line(s) of the markdown:\n", len(parseErrors))
+			for _, lineErr := range parseErrors {
+				fmt.Fprintf(state.output.progress, "  %v\n", lineErr)
+			}
 
-		// Parse original content for comparison
-		originalReader := strings.NewReader(originalContent)
-		originalBoard, err := markdown.FromMarkdown(originalReader, boardSession)
+			switch askParseRecovery(state.output.progress) {
+			case parseRecoverEdit:
+				editorContent = markdown.InsertDiagnostics(userContent, markdown.ParseDiagnostics(parseErrors))
+				continue
+			case parseRecoverApply:
+				// The lines that failed keep their original version, the plan below shows what is left
+				editedContent = parsedContent
+				diffResult, err = diffEditedContent(state, editedContent)
+			default:
+				saveRecovery(state, userContent)
+				fmt.Fprintln(state.output.progress, "No changes applied.")
+				return nil
+			}
+		}
+		var validationErr *markdown.ValidationError
+		if errors.As(err, &validationErr) {
+			fmt.Fprintln(state.output.progress, err)
+			if !askReopen(state.output.progress) {
+				saveRecovery(state, userContent)
+				fmt.Fprintln(state.output.progress, "No changes applied.")
+				return nil
+			}
+			editorContent = markdown.InsertDiagnostics(userContent, validationErr.Diagnostics)
+			continue
+		}
 		if err != nil {
-			fmt.Printf("Error parsing original markdown: %v\n", err)
-			return
+			saveRecovery(state, userContent)
+			return err
+		}
+		if diffResult == nil {
+			return nil
 		}
 
-		// First editor session
-		editedContent, err := openEditorForContent(originalContent, fmt.Sprintf("mdello-%s", safeName))
-		if err != nil {
-			fmt.Printf("Error with editor: %v\n", err)
-			return
+		if !dryRun {
+			conflicts, err = mergeRemoteChanges(state, editedContent, diffResult)
+			if err != nil {
+				saveRecovery(state, userContent)
+				return err
+			}
+		}
+
+		if len(diffResult.QuickActions) == 0 || dryRun {
+			break
+		}
+
+		printPlan(state.output.progress, diffResult.QuickActions)
+		choice := confirmChanges(state.output.progress, len(diffResult.QuickActions))
+		if choice == confirmYes {
+			break
 		}
+		if choice == confirmNo {
+			saveRecovery(state, userContent)
+			fmt.Fprintln(state.output.progress, "No changes applied.")
+			return nil
+		}
+		// Reopen the editor with what the user already wrote
+		editorContent = userContent
+	}
 
-		if editedContent == originalContent {
-			fmt.Println("No changes made.")
-			return
+	if dryRun {
+		printPlan(state.output.progress, diffResult.QuickActions)
+	} else if err := applyQuickActions(state, diffResult); err != nil {
+		return err
+	}
+
+	if len(diffResult.DetailedActions) > 0 {
+		if err := editDetailedActions(state, diffResult.DetailedActions); err != nil {
+			return err
 		}
+	}
+
+	if dryRun {
+		fmt.Fprintln(state.output.progress, "\nDry run, no changes applied.")
+		return nil
+	}
+
+	if len(conflicts) > 0 {
+		return resolveConflicts(state, conflicts)
+	}
+
+	fmt.Fprintln(state.output.progress, "\nBoard updated successfully!")
+	return nil
+}
+
+func init() {
+	boardCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the planned changes without applying them")
+	boardCmd.Flags().StringVarP(&outputFormat, "output", "o", outputText, "Format of the report of applied changes: text or json")
+}
+
+func editDetailedActions(state *boardState, detailedTrelloActions []markdown.DetailedTrelloAction) error {
+	fmt.Fprintf(state.output.progress, "\nFound %d item(s) marked for detailed editing.\n", len(detailedTrelloActions))
+	fmt.Fprintln(state.output.progress, "Generating detailed editor...")
+
+	detailedContent, err := markdown.GenerateDetailedMarkdown(detailedTrelloActions, state.client, cfg)
+	if err != nil {
+		return fmt.Errorf("Failed to generate detailed markdown file: %w", err)
+	}
 
-		// Parse edited content
-		reader := bytes.NewReader([]byte(editedContent))
-		editedBoard, err := markdown.FromMarkdown(reader, boardSession)
+	var detailedActions []markdown.TrelloAction
+	editorContent := detailedContent
+	for {
+		detailedEditedContent, err := openEditorForContent(editorContent, fmt.Sprintf("mdello-%s-detailed", state.safeName()), ".md")
 		if err != nil {
-			fmt.Printf("Error parsing edited markdown: %v\n", err)
-			return
+			return fmt.Errorf("Failed to open editor for detailed edit: %w", err)
 		}
 
-		diffResult, err := diff.QuickActionsDiff(originalBoard, editedBoard, cfg)
+		// Check if user made any changes
+		if detailedEditedContent == detailedContent {
+			fmt.Fprintln(state.output.progress, "No detailed changes made.")
+			return nil
+		}
+
+		detailedActions, err = diff.DetailedActionsDiff(detailedContent, detailedEditedContent, cfg)
 		if err != nil {
-			fmt.Printf("Failed to analyse differences between original and edited content: %v", err)
-			return
+			return fmt.Errorf("Failed to analyze detailed changes: %w", err)
 		}
 
-		if len(diffResult.QuickActions) == 0 && len(diffResult.DetailedActions) == 0 {
-			fmt.Println("No logical changes detected.")
-			return
+		if len(detailedActions) == 0 {
+			fmt.Fprintln(state.output.progress, "No actionable detailed changes detected.")
+			return nil
 		}
 
-		if len(diffResult.QuickActions) > 0 {
-			fmt.Printf("Applying %d quick change(s)...\n", len(diffResult.QuickActions))
-			applyActionsInOrder(diffResult.QuickActions, trelloClient, &markdown.ActionContext{BoardID: cfg.CurrentBoardID})
-			fmt.Println("Quick changes applied!")
+		printPlan(state.output.progress, detailedActions)
+		if dryRun {
+			return nil
+		}
+
+		choice := confirmChanges(state.output.progress, len(detailedActions))
+		if choice == confirmYes {
+			break
+		}
+		if choice == confirmNo {
+			fmt.Fprintln(state.output.progress, "No detailed changes applied.")
+			return nil
 		}
+		editorContent = detailedEditedContent
+	}
 
-		if len(diffResult.DetailedActions) > 0 {
-			fmt.Printf("\nFound %d item(s) marked for detailed editing.\n", len(diffResult.DetailedActions))
-			fmt.Println("Generating detailed editor...")
+	fmt.Fprintf(state.output.progress, "\nApplying %d detailed change(s)...\n", len(detailedActions))
 
-			detailedContent, err := markdown.GenerateDetailedMarkdown(diffResult.DetailedActions, trelloClient, cfg)
-			if err != nil {
-				fmt.Printf("\nFailed to generate detailed markdown file: %v", err)
-				return
-			}
+	err = applyActionsInOrder(state.output.progress, detailedActions, state.client, markdown.NewActionContext(cfg.CurrentBoardID), state.journal, state.report)
+	if err != nil {
+		return fmt.Errorf("Failed to apply detailed changes: %w", err)
+	}
 
-			detailedEditedContent, err := openEditorForContent(detailedContent, fmt.Sprintf("mdello-%s-detailed", safeName))
-			if err != nil {
-				fmt.Printf("\nFailed to open editor for detailed edit: %v", err)
-				return
-			}
+	fmt.Fprintln(state.output.progress, "Detailed changes applied!")
+	return nil
+}
+
+// boardState is a snapshot of the current board taken before any edits are made
+type boardState struct {
+	client          trello.API
+	board           *trello.Board
+	session         *markdown.BoardSession
+	originalContent string
+	originalBoard   *markdown.ParsedBoard
+	journal         *markdown.Journal
+	report          *markdown.ApplyReport
+	output          runOutput
+}
+
+func loadBoardState(output runOutput) (*boardState, error) {
+	if !cfg.HasCredentials() || cfg.CurrentBoardID == "" {
+		return nil, errors.New("No valid cfg found. Please run 'mdello init'.")
+	}
+
+	trelloClient, err := newConfiguredClient()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating trello client: %w", err)
+	}
+
+	snapshot, err := cfg.GetCurrentBoardSnapshot(trelloClient)
+	if err != nil {
+		return nil, fmt.Errorf("Error could not access current board: %w", err)
+	}
+
+	originalContent, boardSession, err := markdown.ToMarkdown(cfg, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("Converting to markdown failed: %w", err)
+	}
+
+	// Parse original content for comparison
+	originalReader := strings.NewReader(originalContent)
+	originalBoard, err := markdown.FromMarkdown(originalReader, boardSession)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing original markdown: %w", err)
+	}
+
+	return &boardState{
+		client:          trelloClient,
+		board:           &snapshot.Board,
+		session:         boardSession,
+		originalContent: originalContent,
+		originalBoard:   originalBoard,
+		journal:         markdown.NewJournal(snapshot.ID),
+		report:          markdown.NewApplyReport(snapshot.ID),
+		output:          output,
+	}, nil
+}
+
+func (s *boardState) safeName() string {
+	safeName := strings.ReplaceAll(s.board.Name, " ", "~")
+	return strings.ReplaceAll(safeName, "/", "~")
+}
 
-			// Check if user made any changes
-			if detailedEditedContent == detailedContent {
-				fmt.Println("No detailed changes made.")
-				return
+// diffEditedContent parses the edited markdown and diffs it against the original snapshot, nil means nothing changed
+func diffEditedContent(state *boardState, editedContent string) (*markdown.DiffResult, error) {
+	reader := bytes.NewReader([]byte(editedContent))
+	editedBoard, err := markdown.FromMarkdown(reader, state.session)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing edited markdown: %w", err)
+	}
+
+	if err := markdown.ValidateBoard(editedBoard, cfg); err != nil {
+		return nil, fmt.Errorf("Cannot apply the edit, %w", err)
+	}
+
+	diffResult, err := diff.QuickActionsDiff(state.originalBoard, editedBoard, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to analyse differences between original and edited content: %w", err)
+	}
+
+	if len(diffResult.QuickActions) == 0 && len(diffResult.DetailedActions) == 0 {
+		fmt.Fprintln(state.output.progress, "No logical changes detected.")
+		return nil, nil
+	}
+
+	return diffResult, nil
+}
+
+func applyQuickActions(state *boardState, diffResult *markdown.DiffResult) error {
+	if len(diffResult.QuickActions) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(state.output.progress, "Applying %d quick change(s)...\n", len(diffResult.QuickActions))
+	err := applyActionsInOrder(state.output.progress, diffResult.QuickActions, state.client, markdown.NewActionContext(cfg.CurrentBoardID), state.journal, state.report)
+	if err != nil {
+		return fmt.Errorf("Failed to apply quick changes: %w", err)
+	}
+	fmt.Fprintln(state.output.progress, "Quick changes applied!")
+
+	return nil
+}
+
+// maxParallelActions bounds how many actions are in flight at once, the client's rate limiter still decides how
+// fast their requests are sent
+const maxParallelActions = 8
+
+func applyActionsInOrder(out io.Writer, actions []markdown.TrelloAction, client trello.API, ctx *markdown.ActionContext, journal *markdown.Journal, report *markdown.ApplyReport) error {
+	appliedCount := 0
+	for len(actions) > 0 {
+		plan, err := markdown.PlanActions(actions)
+		if err != nil {
+			return err
+		}
+
+		failed, errs := applyPlan(out, plan, client, ctx, journal, report)
+		appliedCount += len(actions) - len(failed)
+		if err := journal.SaveErr(); err != nil {
+			fmt.Fprintf(out, "\nWarning: the journal could not be saved, undo and rollback will miss some changes: %v\n", err)
+		}
+		if len(failed) == 0 {
+			return nil
+		}
+
+		err = errors.Join(errs...)
+		skippedCount := 0
+		for _, actionErr := range errs {
+			if errors.Is(actionErr, errDependencyFailed) {
+				skippedCount++
 			}
+		}
+		fmt.Fprintf(out, "\n%d of %d change(s) failed", len(failed)-skippedCount, len(actions))
+		if skippedCount > 0 {
+			fmt.Fprintf(out, ", %d were skipped as they depend on them", skippedCount)
+		}
+		fmt.Fprintln(out, ".")
+		switch askRecovery(out, appliedCount, journal) {
+		case recoverResume:
+			actions = failed // The skipped changes are in failed, they are retried with the changes they depend on
+			report.Retry()
+		case recoverRollback:
+			if rollbackErr := journal.Rollback(client, out); rollbackErr != nil {
+				return fmt.Errorf("%w\nRollback incomplete: %v", err, rollbackErr)
+			}
+			report.RolledBack()
+			fmt.Fprintln(out, "Rolled back the applied changes.")
+			return err
+		default:
+			return err
+		}
+	}
 
-			detailedActions, err := diff.DetailedActionsDiff(detailedContent, detailedEditedContent, cfg)
-			if err != nil {
-				fmt.Printf("\nFailed to analyze detailed changes: %v", err)
-				return
+	return nil
+}
+
+var errDependencyFailed = errors.New("skipped, a change it depends on failed")
+
+// applyPlan applies each stage of the plan through a bounded worker pool, a stage only starts once the one before
+// it has finished. A failure does not stop the run, but the actions that depend on a failed action are skipped
+// rather than applied out of order. The failed and skipped actions are returned in plan order with their errors
+func applyPlan(out io.Writer, plan markdown.Plan, client trello.API, ctx *markdown.ActionContext, journal *markdown.Journal, report *markdown.ApplyReport) ([]markdown.TrelloAction, []error) {
+	total := len(plan.Actions())
+	completed := 0
+	var progressMu sync.Mutex
+
+	// What the failed and skipped actions would have provided
+	unavailable := make(map[string]bool)
+
+	var failed []markdown.TrelloAction
+	var errs []error
+	for _, stage := range plan {
+		results := make([]error, len(stage))
+		var pending []int
+		for i, action := range stage {
+			_, requires := markdown.Dependencies(action)
+			if !slices.ContainsFunc(requires, func(key string) bool { return unavailable[key] }) {
+				pending = append(pending, i)
+				continue
 			}
 
-			if len(detailedActions) > 0 {
-				fmt.Printf("\nApplying %d detailed change(s)...\n", len(detailedActions))
+			results[i] = errDependencyFailed
+			report.RecordSkipped(action, ctx, errDependencyFailed)
+			completed++
+			fmt.Fprintf(out, "[%d/%d] Skipped: %s\n", completed, total, action.Description())
+		}
 
-				for _, action := range detailedActions {
-					if err := action.Apply(trelloClient, &markdown.ActionContext{BoardID: cfg.CurrentBoardID}); err != nil {
-						fmt.Printf("%v", err)
-						return
+		work := make(chan int)
+
+		var wg sync.WaitGroup
+		for range min(maxParallelActions, len(pending)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range work {
+					start := time.Now()
+					results[i] = journal.Apply(client, ctx, stage[i])
+					report.Record(stage[i], ctx, time.Since(start), results[i])
+
+					progressMu.Lock()
+					completed++
+					if results[i] != nil {
+						fmt.Fprintf(out, "[%d/%d] Failed: %s\n", completed, total, stage[i].Description())
+					} else {
+						fmt.Fprintf(out, "[%d/%d] Change: %s\n", completed, total, stage[i].Description())
 					}
+					progressMu.Unlock()
 				}
+			}()
+		}
+		for _, i := range pending {
+			work <- i
+		}
+		close(work)
+		wg.Wait()
 
-				fmt.Println("Detailed changes applied!")
-			} else {
-				fmt.Println("No actionable detailed changes detected.")
+		for i, err := range results {
+			if err == nil {
+				continue
+			}
+			failed = append(failed, stage[i])
+			errs = append(errs, fmt.Errorf("%q: %w", stage[i].Description(), err))
+			provides, _ := markdown.Dependencies(stage[i])
+			for _, key := range provides {
+				unavailable[key] = true
 			}
 		}
-		fmt.Println("\nBoard updated successfully!")
-	},
+	}
+
+	return failed, errs
 }
 
-func applyActionsInOrder(actions []markdown.TrelloAction, client *trello.TrelloClient, ctx *markdown.ActionContext) error {
-	// ===== BOARD AND LABEL CHANGES =====
-	var remainingActions []markdown.TrelloAction
+// askReopen asks whether to reopen the editor with the problems found in the edit, only no gives up on the edit
+func askReopen(out io.Writer) bool {
+	fmt.Fprint(out, "Reopen the editor to fix them? [Y/n]: ")
 
-	for _, action := range actions {
-		switch action.(type) {
-		case markdown.CreateLabelAction, markdown.UpdateBoardNameAction, markdown.UpdateLabelName, markdown.UpdateLabelColour, markdown.DeleteLabelAction:
-			fmt.Printf("Change: %s\n", action.Description())
-			if err := action.Apply(client, ctx); err != nil {
-				return err
-			}
-		default:
-			remainingActions = append(remainingActions, action)
-		}
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Fprintln(out) // For consistent UX line breaks on Ctrl+c & wrong input
+		return false
+	}
+
+	switch strings.TrimSpace(strings.ToLower(response)) {
+	case "n", "no":
+		return false
+	default:
+		return true
 	}
+}
 
-	// ===== LIST CHANGES - CREATE FIRST, THEN MOVE =====
-	var listMoveActions []markdown.TrelloAction
-	var cardActions []markdown.TrelloAction
+type parseRecoverChoice int
 
-	for _, action := range remainingActions {
-		switch action.(type) {
-		case markdown.CreateListAction, markdown.UpdateListNameAction, markdown.ArchiveListAction:
-			// Create and modify lists first
-			fmt.Printf("Change: %s\n", action.Description())
-			if err := action.Apply(client, ctx); err != nil {
-				return err
-			}
-		case markdown.UpdateListPositionAction:
-			// Save position updates for later
-			listMoveActions = append(listMoveActions, action)
-		default:
-			cardActions = append(cardActions, action)
-		}
+const (
+	parseRecoverAbort parseRecoverChoice = iota
+	parseRecoverEdit
+	parseRecoverApply
+)
+
+// askParseRecovery asks what to do with an edit that did not fully parse. Editing again is the default so an
+// accidental Enter does not lose or apply anything
+func askParseRecovery(out io.Writer) parseRecoverChoice {
+	fmt.Fprint(out, "Edit again, apply the lines that could be read or abort? [Edit/apply/abort]: ")
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Fprintln(out) // For consistent UX line breaks on Ctrl+c & wrong input
+		return parseRecoverAbort
 	}
 
-	for _, action := range listMoveActions {
-		fmt.Printf("Change: %s\n", action.Description())
-		if err := action.Apply(client, ctx); err != nil {
-			return err
-		}
+	switch strings.TrimSpace(strings.ToLower(response)) {
+	case "a", "apply":
+		return parseRecoverApply
+	case "abort":
+		return parseRecoverAbort
+	default:
+		return parseRecoverEdit
 	}
+}
 
-	// ===== CARD CHANGES =====
-	for _, action := range cardActions {
-		fmt.Printf("Change: %s\n", action.Description())
-		if err := action.Apply(client, ctx); err != nil {
-			return err
-		}
+type recoverChoice int
+
+const (
+	recoverLeave recoverChoice = iota
+	recoverResume
+	recoverRollback
+)
+
+// askRecovery asks what to do with a partly applied run. Anything other than resume or rollback leaves the board as is,
+// which is also the choice when there is no terminal to ask, e.g. in a script or after 'mdello apply -' read stdin
+func askRecovery(out io.Writer, appliedCount int, journal *markdown.Journal) recoverChoice {
+	fmt.Fprintf(out, "%d change(s) were applied, journal: %s\n", appliedCount, journal.Path())
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintln(out, "Leaving the board as is. Run 'mdello undo' to roll back the applied changes, or apply the file again to retry the failed ones.")
+		return recoverLeave
 	}
+	fmt.Fprint(out, "Roll back the applied changes, retry the failed changes or leave the board as is? [rollback/resume/N]: ")
 
-	return nil
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Fprintln(out) // For consistent UX line breaks on Ctrl+c & wrong input
+		return recoverLeave
+	}
+
+	switch strings.TrimSpace(strings.ToLower(response)) {
+	case "rollback":
+		return recoverRollback
+	case "resume":
+		return recoverResume
+	default:
+		return recoverLeave
+	}
 }
 
-func openEditorForContent(content string, filePrefix string) (string, error) {
-	tempFile, err := os.CreateTemp("", fmt.Sprintf("%s-*.md", filePrefix))
+// openEditorForContent edits content in a temp file, extension lets the editor pick the right syntax highlighting
+func openEditorForContent(content string, filePrefix string, extension string) (string, error) {
+	tempFile, err := os.CreateTemp("", fmt.Sprintf("%s-*%s", filePrefix, extension))
 	if err != nil {
 		return "", fmt.Errorf("error creating temp file: %w", err)
 	}
@@ -206,8 +551,9 @@ func openEditorForContent(content string, filePrefix string) (string, error) {
 		return "", fmt.Errorf("error getting editor: %w", err)
 	}
 
-	// Open editor
-	editorCmd := exec.Command(editor, tempFile.Name())
+	// Open editor, it may have arguments such as code --wait
+	editorArgs := append(strings.Fields(editor), tempFile.Name())
+	editorCmd := exec.Command(editorArgs[0], editorArgs[1:]...)
 	editorCmd.Stdin = os.Stdin
 	editorCmd.Stdout = os.Stdout
 	editorCmd.Stderr = os.Stderr
@@ -224,7 +570,12 @@ func openEditorForContent(content string, filePrefix string) (string, error) {
 	return string(editedContent), nil
 }
 
+// getEditor returns the profile's editor, or git's editor which falls back to $VISUAL and $EDITOR
 func getEditor() (string, error) {
+	if cfg != nil && strings.TrimSpace(cfg.Editor) != "" {
+		return cfg.Editor, nil
+	}
+
 	cmd := exec.Command("git", "var", "GIT_EDITOR")
 	output, err := cmd.Output()
 	if err != nil {
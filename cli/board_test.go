@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/vinzmyko/mdello/config"
+	"github.com/vinzmyko/mdello/markdown"
+	"github.com/vinzmyko/mdello/trello/trellotest"
+)
+
+func TestApplyPlanSkipsTheDependentsOfFailedActions(t *testing.T) {
+	t.Setenv(config.EnvHome, t.TempDir())
+	server := trellotest.NewServer()
+	defer server.Close()
+	board := server.AddBoard("Project")
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	actions := []markdown.TrelloAction{
+		markdown.CreateListAction{ListID: "NEW_ITEM_1", BoardID: board.ID, Name: "Later"},
+		markdown.CreateCardAction{CardID: "NEW_ITEM_2", ListID: "NEW_ITEM_1", ListName: "Later", Name: "Dark mode"},
+		markdown.CreateChecklistAction{ChecklistID: "NEW_ITEM_3", CardID: "NEW_ITEM_2", CardName: "Dark mode", Name: "Steps"},
+	}
+	plan, err := markdown.PlanActions(actions)
+	if err != nil {
+		t.Fatalf("PlanActions() failed: %v", err)
+	}
+
+	ctx := markdown.NewActionContext(board.ID)
+	journal := markdown.NewJournal(board.ID)
+	report := markdown.NewApplyReport(board.ID)
+	server.FailNext(1, http.StatusBadRequest)
+	failed, errs := applyPlan(io.Discard, plan, client, ctx, journal, report)
+
+	if len(failed) != 3 || len(errs) != 3 {
+		t.Fatalf("applyPlan() returned %d failed action(s) and %d error(s), want 3 of each", len(failed), len(errs))
+	}
+	if errors.Is(errs[0], errDependencyFailed) || !errors.Is(errs[1], errDependencyFailed) || !errors.Is(errs[2], errDependencyFailed) {
+		t.Errorf("want the list to fail and the card and checklist to be skipped, got %v", errs)
+	}
+	if report.Count(markdown.JournalFailed) != 1 || report.Count(markdown.JournalSkipped) != 2 {
+		t.Errorf("the report has %d failed and %d skipped, want 1 and 2", report.Count(markdown.JournalFailed), report.Count(markdown.JournalSkipped))
+	}
+	if len(journal.Entries) != 1 {
+		t.Errorf("the skipped actions were applied, the journal has %d entries", len(journal.Entries))
+	}
+
+	// Resuming retries the skipped actions with the one they depend on
+	report.Retry()
+	plan, err = markdown.PlanActions(failed)
+	if err != nil {
+		t.Fatalf("PlanActions() failed: %v", err)
+	}
+	if failed, errs := applyPlan(io.Discard, plan, client, ctx, journal, report); len(failed) != 0 {
+		t.Fatalf("resuming failed: %v", errs)
+	}
+	if report.Count(markdown.JournalApplied) != 3 || len(report.Results) != 3 {
+		t.Errorf("the report after resuming has %d results, %d applied, want 3 applied", len(report.Results), report.Count(markdown.JournalApplied))
+	}
+	if snapshot := server.Snapshot(board.ID); len(snapshot.Lists) != 1 || len(snapshot.Cards) != 1 || len(snapshot.Checklists) != 1 {
+		t.Errorf("the board after resuming has %d list(s), %d card(s) and %d checklist(s), want one of each", len(snapshot.Lists), len(snapshot.Cards), len(snapshot.Checklists))
+	}
+}
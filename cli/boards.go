@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -11,28 +12,24 @@ import (
 var boardsCmd = &cobra.Command{
 	Use:   "boards",
 	Short: "Get all current users boards",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cfg == nil || cfg.Token == "" {
-			fmt.Println("No valid configuration found. Please run 'mdello init'.")
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cfg.HasCredentials() {
+			return errors.New("No valid configuration found. Please run 'mdello init'.")
 		}
 
-		trelloClient, err := trello.NewTrelloClient(apiKey, cfg.Token)
+		trelloClient, err := newConfiguredClient()
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
 		boards, err := trelloClient.GetBoards()
 		if err != nil {
-			fmt.Println(err)
-			return
+			return fmt.Errorf("Error could not get boards: %w", err)
 		}
 
 		if cfg.CurrentBoardID != "" {
 			currentBoard, err := cfg.GetCurrentBoard(trelloClient)
 			if err != nil {
-				fmt.Printf("Error could not access current board: %v", err)
-				return
+				return fmt.Errorf("Error could not access current board: %w", err)
 			}
 			fmt.Printf("Current board: %s\n\n", currentBoard.Name)
 		} else {
@@ -53,7 +50,7 @@ var boardsCmd = &cobra.Command{
 		err = survey.AskOne(boardPrompt, &selectedBoardName)
 		if err != nil {
 			fmt.Println("\nBoard selection cancelled.")
-			return
+			return nil
 		}
 		var selectedBoard *trello.Board
 		for _, board := range boards {
@@ -63,17 +60,16 @@ var boardsCmd = &cobra.Command{
 			}
 		}
 		if selectedBoard == nil {
-			fmt.Println("Error: selected board not found")
-			return
+			return errors.New("Error: selected board not found")
 		}
 
 		cfg.UpdateBoardID(selectedBoard.ID)
 
 		err = cfg.Save()
 		if err != nil {
-			fmt.Printf("Error saving configuration: %v\n", err)
-			return
+			return fmt.Errorf("Error saving configuration: %w", err)
 		}
 		fmt.Printf("Current board updated to: %s\n", selectedBoard.Name)
+		return nil
 	},
 }
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/vinzmyko/mdello/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change preferences such as the date format and editor",
+	Long:  "Preferences belong to the profile in use, pick another with --profile. Run 'mdello config list' to see every key.",
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a preference, empty when it is the default",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := cfg.Get(args[0])
+		if err != nil {
+			return unknownSettingError(args[0])
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a preference, an empty value resets it to the default",
+	Example: `  mdello config set date_format eu
+  mdello config set editor "code --wait"
+  mdello config set time_zone ""`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+		if err := cfg.Set(key, value); err != nil {
+			if errors.Is(err, config.ErrUnknownSetting) {
+				return unknownSettingError(key)
+			}
+			return fmt.Errorf("Cannot set %s: %w", key, err)
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("Error saving configuration: %w", err)
+		}
+
+		value, _ = cfg.Get(key)
+		if value == "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s reset to the default.\n", key)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s set to %s.\n", key, value)
+		}
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the preferences of the profile in use",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Profile: %s\n\n", cfg.ProfileName())
+
+		writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "KEY\tVALUE\tDESCRIPTION")
+		for _, setting := range config.Settings {
+			value, _ := cfg.Get(setting.Key)
+			if value == "" {
+				value = "-"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", setting.Key, value, setting.Description)
+		}
+		return writer.Flush()
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the config file, it is checked before it is saved",
+	Args:  cobra.NoArgs,
+	// The file is edited as a whole, so a file that does not load can still be fixed here
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := loadConfig(cmd, args); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%v\n\n", err)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configFile, err := config.Path()
+		if err != nil {
+			return fmt.Errorf("Error finding configuration: %w", err)
+		}
+
+		data, err := os.ReadFile(configFile)
+		if errors.Is(err, os.ErrNotExist) {
+			data = []byte("{\n}\n")
+		} else if err != nil {
+			return fmt.Errorf("Error reading configuration: %w", err)
+		}
+
+		out := cmd.OutOrStdout()
+		content := string(data)
+		for {
+			editedContent, err := openEditorForContent(content, "mdello-config", ".json")
+			if err != nil {
+				return err
+			}
+			if editedContent == string(data) {
+				fmt.Fprintln(out, "No changes made.")
+				return nil
+			}
+
+			edited, err := config.ParseConfig([]byte(editedContent))
+			if err == nil {
+				if err := config.SaveConfig(*edited); err != nil {
+					return fmt.Errorf("Error saving configuration: %w", err)
+				}
+				fmt.Fprintf(out, "Configuration saved to %s.\n", configFile)
+				return nil
+			}
+
+			fmt.Fprintf(out, "The configuration has problems:\n%v\n\n", err)
+			if !askReopen(out) {
+				fmt.Fprintln(out, "Edit discarded, the configuration was not changed.")
+				return nil
+			}
+			content = editedContent
+		}
+	},
+}
+
+func unknownSettingError(key string) error {
+	keys := make([]string, len(config.Settings))
+	for i, setting := range config.Settings {
+		keys[i] = setting.Key
+	}
+	return fmt.Errorf("Unknown key '%s'. Available keys: %s.", key, strings.Join(keys, ", "))
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+}
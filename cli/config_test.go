@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vinzmyko/mdello/config"
+)
+
+func TestConfigCommandsWriteToTheCommandOutput(t *testing.T) {
+	t.Setenv(config.EnvHome, t.TempDir())
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"config", "set", "date_format", "eu"}, "date_format set to 02-01-2006 15:04.\n"},
+		{[]string{"config", "get", "date_format"}, "02-01-2006 15:04\n"},
+		{[]string{"config", "set", "date_format", ""}, "date_format reset to the default.\n"},
+		{[]string{"config", "get", "date_format"}, "\n"},
+	}
+	for _, test := range tests {
+		stdout, stderr, exitCode := runMdello(t, test.args...)
+		if exitCode != 0 {
+			t.Fatalf("%s exited with %d: %s", strings.Join(test.args, " "), exitCode, stderr)
+		}
+		if stdout != test.want {
+			t.Errorf("%s wrote %q, want %q", strings.Join(test.args, " "), stdout, test.want)
+		}
+	}
+
+	stdout, _, _ := runMdello(t, "config", "list")
+	if !strings.HasPrefix(stdout, "Profile: default\n") || !strings.Contains(stdout, "date_format") {
+		t.Errorf("config list wrote:\n%s", stdout)
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+var resolvedToken string
+
+// trelloToken resolves the configured token once per run, so a token command or passphrase prompt only runs once.
+// The token is never written back to the config
+func trelloToken() (string, error) {
+	if resolvedToken != "" {
+		return resolvedToken, nil
+	}
+
+	token, err := cfg.ResolveToken(promptPassphrase)
+	if err != nil {
+		return "", fmt.Errorf("Could not get your Trello token: %w", err)
+	}
+	resolvedToken = token
+	return token, nil
+}
+
+func promptPassphrase() (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", errors.New("the token file needs a passphrase but there is no terminal to ask for it, use token_command in scripts")
+	}
+
+	fmt.Fprint(os.Stderr, "Passphrase for the token file: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("could not read passphrase: %w", err)
+	}
+	// Not trimmed, the passphrase is used exactly as it was typed when the file was encrypted
+	return string(passphrase), nil
+}
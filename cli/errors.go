@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vinzmyko/mdello/trello"
+)
+
+// Exit codes, scripts can tell a connection problem apart from a bad edit without reading the message
+const (
+	exitError        = 1
+	exitOffline      = 3
+	exitTimeout      = 4
+	exitUnauthorized = 5
+	exitForbidden    = 6
+	exitUnavailable  = 7
+)
+
+type failure struct {
+	err      error
+	exitCode int
+	message  string
+}
+
+var failures = []failure{
+	{trello.ErrUnauthorized, exitUnauthorized, "Trello rejected your token, it may have expired or been revoked. Run 'mdello init' to set a new one."},
+	{trello.ErrForbidden, exitForbidden, "Your token does not have permission for this. Check the board is shared with you and the token was granted write access."},
+	{trello.ErrTimeout, exitTimeout, "Trello took too long to respond. Check your connection and try again."},
+	{trello.ErrOffline, exitOffline, "Could not reach Trello. Check your internet connection and try again."},
+	{trello.ErrUnavailable, exitUnavailable, "Trello is having problems right now. Try again later, see https://www.atlassian.com/trello/status."},
+}
+
+// describeError turns an error returned by a command into the message to print and the exit code. Known trello
+// failures get a message saying what to do, the underlying error is only shown with --verbose
+func describeError(err error) (string, int) {
+	for _, failure := range failures {
+		if errors.Is(err, failure.err) {
+			if verbose {
+				return fmt.Sprintf("%s\n%v", failure.message, err), failure.exitCode
+			}
+			return failure.message, failure.exitCode
+		}
+	}
+	return err.Error(), exitError
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -17,8 +18,8 @@ import (
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialise mdello with your Trello token",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cfg != nil && cfg.Token != "" {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.HasCredentials() {
 			fmt.Print("Configuration already exists. Overwrite? (y/N): ")
 
 			reader := bufio.NewReader(os.Stdin)
@@ -26,112 +27,207 @@ var initCmd = &cobra.Command{
 			if err != nil {
 				fmt.Println() // For consistent UX line breaks on Ctrl+c & wrong input
 				printCancelled()
-				return
+				return nil
 			}
 			response = strings.TrimSpace(strings.ToLower(response))
 
 			if response != "y" && response != "yes" {
 				printCancelled()
-				return
+				return nil
 			}
 		}
 
-		fmt.Println("Enter your Trello Token: ")
+		setup, err := setupProfile(cfg.ProfileName())
+		if setup == nil || err != nil {
+			return err
+		}
 
-		tokenBytes, err := term.ReadPassword(int(syscall.Stdin))
-		if err != nil {
-			fmt.Println()
-			printCancelled()
-			return
+		// Init only asks for the token, board and date format, the profile's other settings are kept
+		profile, _ := cfg.LookupProfile(cfg.ProfileName())
+		profile.Token = setup.Token
+		profile.TokenCommand = setup.TokenCommand
+		profile.TokenFile = setup.TokenFile
+		profile.CurrentBoardID = setup.CurrentBoardID
+		profile.DateFormat = setup.DateFormat
+
+		cfg.SetProfile(cfg.ProfileName(), profile)
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("Error: Could not save configuration: %w", err)
 		}
+		fmt.Println("\nAll options have successfully been saved!")
 		fmt.Println()
+		return nil
+	},
+}
 
-		token := strings.TrimSpace(string(tokenBytes))
+// setupProfile asks for the token, board and date format of a profile. It returns nil if the user cancels
+func setupProfile(profileName string) (*config.Profile, error) {
+	var storage string
+	storagePrompt := &survey.Select{
+		Message: "How should mdello get your Trello token?",
+		Options: []string{tokenStorageCommand, tokenStorageEncrypted, tokenStoragePlain},
+		VimMode: true,
+	}
+	if err := survey.AskOne(storagePrompt, &storage); err != nil {
+		printStepCancelled("Token storage")
+		return nil, nil
+	}
+
+	credentials, token, err := askToken(storage)
+	if errors.Is(err, errInitCancelled) {
+		printCancelled()
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	trelloClient, err := newTrelloClient(token)
+	if err != nil {
+		fmt.Println()
+		return nil, err
+	}
+	fmt.Println("Token registered successfully!")
+	boards, err := trelloClient.GetBoards()
+	if err != nil {
+		fmt.Println()
+		return nil, fmt.Errorf("Could not access boards: %w", err)
+	}
+	if len(boards) < 1 {
+		fmt.Println("\nUser has no boards")
+		fmt.Println()
+		return nil, nil
+	}
+
+	boardOptions := make([]string, 0, len(boards))
+	for _, board := range boards {
+		boardOptions = append(boardOptions, board.Name)
+	}
+
+	var selectedBoardName string
+	boardPrompt := &survey.Select{
+		Message: "Select a board:",
+		Options: boardOptions,
+		VimMode: true,
+	}
+	err = survey.AskOne(boardPrompt, &selectedBoardName)
+	if err != nil {
+		printStepCancelled("Current board")
+		return nil, nil
+	}
+
+	var selectedBoard *trello.Board
+	for _, board := range boards {
+		if board.Name == selectedBoardName {
+			selectedBoard = &board
+			break
+		}
+	}
+	if selectedBoard == nil {
+		fmt.Println("\nError: selected board not found")
+		fmt.Println()
+		return nil, nil
+	}
+	fmt.Printf("\n%s selected.", selectedBoardName)
+
+	var selectedDateFormatDisplay string
+	boardPrompt = &survey.Select{
+		Message: "Select a date format:",
+		Options: config.GetDisplayOptions(),
+		VimMode: true,
+	}
+	err = survey.AskOne(boardPrompt, &selectedDateFormatDisplay)
+	if err != nil {
+		printStepCancelled("Date format")
+		return nil, nil
+	}
+	actualDateFormat, found := config.GetFormatFromDisplay(selectedDateFormatDisplay)
+	if !found {
+		fmt.Println("\nError: Invalid date format selected")
+		fmt.Println()
+		return nil, nil
+	}
+	fmt.Printf("\n%s selected.", selectedDateFormatDisplay)
 
-		trelloClient, err := trello.NewTrelloClient(apiKey, token)
-		if err != nil {
-			fmt.Println("\nThe provided token was invalid. Please try again.")
-			fmt.Println()
-			return
+	if storage == tokenStorageEncrypted {
+		fmt.Println()
+		credentials.TokenFile, err = saveEncryptedToken(profileName, token)
+		if errors.Is(err, errInitCancelled) {
+			printStepCancelled("Passphrase")
+			return nil, nil
 		}
-		fmt.Println("Token registered successfully!")
-		boards, err := trelloClient.GetBoards()
 		if err != nil {
-			fmt.Printf("\nCould not access boards: %v", err)
-			fmt.Println()
-			return
-		}
-		if len(boards) < 1 {
-			fmt.Println("\nUser has no boards")
-			fmt.Println()
-			return
+			return nil, err
 		}
+	}
 
-		boardOptions := make([]string, 0, len(boards))
-		for _, board := range boards {
-			boardOptions = append(boardOptions, board.Name)
-		}
+	credentials.CurrentBoardID = selectedBoard.ID
+	credentials.DateFormat = actualDateFormat
+	return &credentials, nil
+}
 
-		var selectedBoardName string
-		boardPrompt := &survey.Select{
-			Message: "Select a board:",
-			Options: boardOptions,
-			VimMode: true,
-		}
-		err = survey.AskOne(boardPrompt, &selectedBoardName)
-		if err != nil {
-			printStepCancelled("Current board")
-			return
-		}
+// Where init keeps the token, only the last option stores it in config.json
+const (
+	tokenStorageCommand   = "Run a command that prints it, e.g. pass show trello"
+	tokenStorageEncrypted = "Encrypted file unlocked with a passphrase"
+	tokenStoragePlain     = "Plain text in config.json"
+)
 
-		var selectedBoard *trello.Board
-		for _, board := range boards {
-			if board.Name == selectedBoardName {
-				selectedBoard = &board
-				break
-			}
-		}
-		if selectedBoard == nil {
-			fmt.Println("\nError: selected board not found")
-			fmt.Println()
-			return
-		}
-		fmt.Printf("\n%s selected.", selectedBoardName)
+var errInitCancelled = errors.New("init cancelled")
 
-		var selectedDateFormatDisplay string
-		boardPrompt = &survey.Select{
-			Message: "Select a date format:",
-			Options: config.GetDisplayOptions(),
-			VimMode: true,
+// askToken gets the token for the chosen storage and returns the config fields that point at it
+func askToken(storage string) (config.Profile, string, error) {
+	if storage == tokenStorageCommand {
+		var command string
+		commandPrompt := &survey.Input{Message: "Command that prints your Trello token:"}
+		if err := survey.AskOne(commandPrompt, &command, survey.WithValidator(survey.Required)); err != nil {
+			return config.Profile{}, "", errInitCancelled
 		}
-		err = survey.AskOne(boardPrompt, &selectedDateFormatDisplay)
+
+		credentials := config.Profile{TokenCommand: strings.TrimSpace(command)}
+		token, err := credentials.ResolveToken(nil)
 		if err != nil {
-			printStepCancelled("Date format")
-			return
-		}
-		actualDateFormat, found := config.GetFormatFromDisplay(selectedDateFormatDisplay)
-		if !found {
-			fmt.Println("\nError: Invalid date format selected")
-			fmt.Println()
-			return
+			return config.Profile{}, "", fmt.Errorf("Could not get your Trello token: %w", err)
 		}
-		fmt.Printf("\n%s selected.", selectedDateFormatDisplay)
+		return credentials, token, nil
+	}
 
-		cfg := &config.Config{
-			Token:          token,
-			CurrentBoardID: selectedBoard.ID,
-			DateFormat:     actualDateFormat,
-		}
-		fmt.Println("\nAll options have successfully been saved!")
-		fmt.Println()
+	fmt.Println("Enter your Trello Token: ")
+	tokenBytes, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println()
+	if err != nil {
+		return config.Profile{}, "", errInitCancelled
+	}
+
+	token := strings.TrimSpace(string(tokenBytes))
+	if storage == tokenStoragePlain {
+		return config.Profile{Token: token}, token, nil
+	}
+	return config.Profile{}, token, nil
+}
 
-		err = config.SaveConfig(*cfg)
-		if err != nil {
-			fmt.Printf("\nError: Could not save configuration: %v\n", err)
-			fmt.Println()
-			return
-		}
-	},
+// saveEncryptedToken asks for a passphrase and writes the encrypted token file, returning its path
+func saveEncryptedToken(profileName, token string) (string, error) {
+	var passphrase, confirmation string
+	passphrasePrompt := &survey.Password{Message: "Passphrase for the token file (8 characters or more):"}
+	if err := survey.AskOne(passphrasePrompt, &passphrase, survey.WithValidator(survey.MinLength(8))); err != nil {
+		return "", errInitCancelled
+	}
+	if err := survey.AskOne(&survey.Password{Message: "Repeat the passphrase:"}, &confirmation); err != nil {
+		return "", errInitCancelled
+	}
+	if passphrase != confirmation {
+		return "", errors.New("The passphrases do not match, run 'mdello init' again.")
+	}
+
+	path, err := config.DefaultTokenFile(profileName)
+	if err != nil {
+		return "", err
+	}
+	if err := config.EncryptTokenFile(path, token, passphrase); err != nil {
+		return "", fmt.Errorf("Error: Could not save the token file: %w", err)
+	}
+	return path, nil
 }
 
 func printCancelled() {
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vinzmyko/mdello/markdown"
+	"github.com/vinzmyko/mdello/markdown/diff"
+)
+
+// mergeRemoteChanges re-fetches the board before the plan is shown, since teammates may have changed it while the
+// editor was open. Changes to items that were also changed on trello are removed from diffResult and returned as
+// conflicts
+func mergeRemoteChanges(state *boardState, editedContent string, diffResult *markdown.DiffResult) ([]markdown.Conflict, error) {
+	remoteSnapshot, err := cfg.GetCurrentBoardSnapshot(state.client)
+	if err != nil {
+		return nil, fmt.Errorf("Error could not access current board: %w", err)
+	}
+
+	remoteContent, remoteSession, err := markdown.ToMarkdown(cfg, remoteSnapshot)
+	if err != nil {
+		return nil, fmt.Errorf("Converting to markdown failed: %w", err)
+	}
+	if remoteContent == state.originalContent {
+		return nil, nil
+	}
+
+	remoteParsed, err := markdown.FromMarkdown(strings.NewReader(remoteContent), remoteSession)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing current board markdown: %w", err)
+	}
+	editedBoard, err := markdown.FromMarkdown(strings.NewReader(editedContent), state.session)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing edited markdown: %w", err)
+	}
+
+	mergedBoard, mergeConflicts := diff.ThreeWayMerge(state.originalBoard, editedBoard, remoteParsed)
+	if len(mergeConflicts) == 0 {
+		fmt.Fprintln(state.output.progress, "\nThe board was changed on Trello while you were editing, none of the changes conflict with yours.")
+		return nil, nil
+	}
+
+	mergedResult, err := diff.QuickActionsDiff(state.originalBoard, mergedBoard, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to analyse differences between original and edited content: %w", err)
+	}
+	*diffResult = *mergedResult
+
+	conflicts := make([]markdown.Conflict, 0, len(mergeConflicts))
+	for _, mergeConflict := range mergeConflicts {
+		shortID := state.session.GetShortID(mergeConflict.ID)
+		conflict := markdown.Conflict{
+			Kind:    mergeConflict.Kind,
+			ShortID: shortID,
+			Local:   markdown.ConflictBlock(editedContent, mergeConflict.Kind, shortID),
+			Remote:  markdown.ConflictBlock(remoteContent, mergeConflict.Kind, shortID),
+		}
+		if mergeConflict.Kind == markdown.ConflictCard {
+			conflict.ListShortID = state.session.GetShortID(editedListID(editedBoard, mergeConflict.ID))
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	fmt.Fprintf(state.output.progress, "\n%d item(s) were also changed on Trello while you were editing, they are left out and reopened for you to resolve.\n", len(conflicts))
+	return conflicts, nil
+}
+
+func editedListID(editedBoard *markdown.ParsedBoard, cardID string) string {
+	for _, list := range editedBoard.Lists {
+		for _, card := range list.Cards {
+			if card.ID == cardID {
+				return list.ID
+			}
+		}
+	}
+	return ""
+}
+
+// resolveConflicts reopens the editor on the updated board with both versions of each conflicting item. Changes
+// made while resolving are added to the report of the run that found the conflicts
+func resolveConflicts(previous *boardState, conflicts []markdown.Conflict) error {
+	state, err := loadBoardState(previous.output)
+	if err != nil {
+		return err
+	}
+	state.report = previous.report
+
+	fmt.Fprintln(state.output.progress, "\nOpening the editor to resolve the conflicts...")
+	return editBoard(state, markdown.InsertConflictMarkers(state.originalContent, conflicts))
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/vinzmyko/mdello/markdown"
+)
+
+type changeKind int
+
+const (
+	changeCreate changeKind = iota
+	changeUpdate
+	changeDelete
+)
+
+var planGroups = []string{"Board", "Labels", "Lists", "Cards"}
+
+// printPlan lists every action grouped by the kind of trello object it changes, similar to `terraform plan`
+func printPlan(out io.Writer, actions []markdown.TrelloAction) {
+	grouped := make(map[string][]markdown.TrelloAction)
+	for _, action := range actions {
+		group := actionGroup(action)
+		grouped[group] = append(grouped[group], action)
+	}
+
+	var creates, updates, deletes int
+
+	fmt.Fprintln(out, "\nPlanned changes:")
+	for _, group := range planGroups {
+		if len(grouped[group]) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(out, "\n%s:\n", group)
+		for _, action := range grouped[group] {
+			symbol := "~"
+			switch actionKind(action) {
+			case changeCreate:
+				symbol = "+"
+				creates++
+			case changeDelete:
+				symbol = "-"
+				deletes++
+			default:
+				updates++
+			}
+			fmt.Fprintf(out, "  %s %s\n", symbol, action.Description())
+		}
+	}
+
+	fmt.Fprintf(out, "\nPlan: %d to create, %d to change, %d to delete.\n", creates, updates, deletes)
+}
+
+func actionGroup(action markdown.TrelloAction) string {
+	switch action.(type) {
+	case markdown.UpdateBoardNameAction, markdown.DetailedUpdateBoardAction:
+		return "Board"
+	case markdown.CreateLabelAction, markdown.UpdateLabelName, markdown.UpdateLabelColour, markdown.DeleteLabelAction:
+		return "Labels"
+	case markdown.CreateListAction, markdown.UpdateListNameAction, markdown.UpdateListPositionAction,
+		markdown.ArchiveListAction, markdown.DetailedUpdateListAction:
+		return "Lists"
+	default:
+		return "Cards"
+	}
+}
+
+func actionKind(action markdown.TrelloAction) changeKind {
+	switch act := action.(type) {
+	case markdown.CreateLabelAction, markdown.CreateListAction, markdown.CreateCardAction, markdown.AddCardLabelAction,
+		markdown.CreateChecklistAction, markdown.CreateCheckItemAction:
+		return changeCreate
+	case markdown.DeleteLabelAction, markdown.DeleteCardAction, markdown.DeleteCardLabelAction, markdown.DeleteCardDueDate,
+		markdown.DeleteChecklistAction, markdown.DeleteCheckItemAction:
+		return changeDelete
+	case markdown.ArchiveListAction:
+		if act.Value {
+			return changeDelete
+		}
+		return changeUpdate
+	default:
+		return changeUpdate
+	}
+}
+
+type confirmChoice int
+
+const (
+	confirmNo confirmChoice = iota
+	confirmYes
+	confirmEdit
+)
+
+// confirmChanges asks the user whether to apply the planned changes. Anything other than yes or edit cancels
+func confirmChanges(out io.Writer, count int) confirmChoice {
+	fmt.Fprintf(out, "\nApply these %d change(s)? [y/N/edit]: ", count)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Fprintln(out) // For consistent UX line breaks on Ctrl+c & wrong input
+		return confirmNo
+	}
+
+	switch strings.TrimSpace(strings.ToLower(response)) {
+	case "y", "yes":
+		return confirmYes
+	case "e", "edit":
+		return confirmEdit
+	default:
+		return confirmNo
+	}
+}
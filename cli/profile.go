@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/vinzmyko/mdello/config"
+)
+
+var profileNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage profiles for different Trello accounts",
+	Long:  "Each profile has its own token, current board, date format and editor. Pick one for a single command with --profile.",
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a profile, asking for its token, board and date format",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !profileNameRegex.MatchString(name) {
+			return fmt.Errorf("Profile names can only contain letters, numbers, '-' and '_'.")
+		}
+		if _, found := cfg.LookupProfile(name); found {
+			return fmt.Errorf("Profile '%s' already exists. Run 'mdello --profile %s init' to set it up again.", name, name)
+		}
+
+		profile, err := setupProfile(name)
+		if profile == nil || err != nil {
+			return err
+		}
+
+		cfg.SetProfile(name, *profile)
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("Error saving configuration: %w", err)
+		}
+		fmt.Printf("\nProfile '%s' added. Use it with --profile %s or make it the default with 'mdello profile use %s'.\n", name, name, name)
+		return nil
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles, the one in use is marked with *",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "  PROFILE\tTOKEN\tBOARD")
+		for _, name := range cfg.ProfileNames() {
+			profile, _ := cfg.LookupProfile(name)
+			marker := " "
+			if name == cfg.ProfileName() {
+				marker = "*"
+			}
+			boardID := profile.CurrentBoardID
+			if boardID == "" {
+				boardID = "-"
+			}
+			fmt.Fprintf(writer, "%s %s\t%s\t%s\n", marker, name, profile.TokenSource(), boardID)
+		}
+		return writer.Flush()
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Use a profile when --profile is not given",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := cfg.UseProfile(name); err != nil {
+			return fmt.Errorf("Profile '%s' not found.", name)
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("Error saving configuration: %w", err)
+		}
+		fmt.Printf("Now using profile '%s'.\n", name)
+		return nil
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a profile and the encrypted token file init created for it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		profile, found := cfg.LookupProfile(name)
+		if !found {
+			return fmt.Errorf("Profile '%s' not found.", name)
+		}
+		if err := cfg.RemoveProfile(name); err != nil {
+			return fmt.Errorf("Cannot remove profile '%s': %w", name, err)
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("Error saving configuration: %w", err)
+		}
+
+		// Token files outside the one init writes may be shared, so they are left alone
+		if tokenFile, err := config.DefaultTokenFile(name); err == nil && profile.TokenFile == tokenFile {
+			if err := os.Remove(tokenFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+				fmt.Printf("Could not remove the token file %s: %v\n", tokenFile, err)
+			}
+		}
+		fmt.Printf("Profile '%s' removed.\n", name)
+		return nil
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(profileAddCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileRemoveCmd)
+}
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var pullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Print current board as markdown",
+	Long:  "Print the current board as markdown to stdout, e.g. 'mdello pull > board.md'. The output can be edited and applied with 'mdello apply'.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Anything but the markdown goes to stderr, so redirecting stdout to a file only ever writes the board
+		state, err := loadBoardState(runOutput{progress: cmd.ErrOrStderr(), report: cmd.ErrOrStderr()})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(cmd.OutOrStdout(), state.originalContent)
+		return nil
+	},
+}
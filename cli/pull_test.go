@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vinzmyko/mdello/config"
+)
+
+func TestPullWritesOnlyTheBoardToStdout(t *testing.T) {
+	newTestBoard(t, false)
+
+	stdout, stderr, exitCode := runMdello(t, "pull")
+	if exitCode != 0 {
+		t.Fatalf("pull exited with %d: %s", exitCode, stderr)
+	}
+	if !strings.HasPrefix(stdout, "# Project") || stderr != "" {
+		t.Errorf("pull wrote:\n%s\nto stdout and:\n%s\nto stderr, want only the board on stdout", stdout, stderr)
+	}
+
+	t.Setenv(config.EnvBoard, "missing")
+	stdout, stderr, exitCode = runMdello(t, "pull")
+	if exitCode == 0 {
+		t.Error("pull of a missing board exited with 0")
+	}
+	if stdout != "" || !strings.Contains(stderr, "Error could not access current board") {
+		t.Errorf("pull of a missing board wrote:\n%s\nto stdout and:\n%s\nto stderr, want the error on stderr only", stdout, stderr)
+	}
+}
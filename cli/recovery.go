@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vinzmyko/mdello/config"
+)
+
+// saveRecovery keeps an abandoned edit in ~/.mdello/recovery/, the editor's temp file is deleted once it closes
+func saveRecovery(state *boardState, content string) {
+	if content == state.originalContent {
+		return
+	}
+
+	path, err := writeRecoveryFile(state.board.ID, content)
+	if err != nil {
+		fmt.Fprintf(state.output.progress, "Could not save your edit: %v\n", err)
+		return
+	}
+	fmt.Fprintf(state.output.progress, "Your edit was saved to %s, apply it later with 'mdello apply %s'.\n", path, path)
+}
+
+func writeRecoveryFile(boardID, content string) (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	recoveryDir := filepath.Join(configDir, "recovery")
+	if err := os.MkdirAll(recoveryDir, 0700); err != nil {
+		return "", fmt.Errorf("could not create recovery directory: %w", err)
+	}
+
+	path := filepath.Join(recoveryDir, fmt.Sprintf("%s-%s.md", boardID, time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return "", fmt.Errorf("could not write recovery file: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vinzmyko/mdello/markdown"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var outputFormat string
+
+// runOutput is where a run writes its progress, plans and prompts, and where it writes the report
+type runOutput struct {
+	progress io.Writer
+	report   io.Writer
+}
+
+func textOutput(cmd *cobra.Command) runOutput {
+	return runOutput{progress: cmd.OutOrStdout(), report: cmd.OutOrStdout()}
+}
+
+// setupOutput picks the writers for --output. With json everything but the report is sent to stderr, so stdout
+// only holds the JSON report
+func setupOutput(cmd *cobra.Command) (runOutput, error) {
+	switch outputFormat {
+	case outputText:
+		return textOutput(cmd), nil
+	case outputJSON:
+		return runOutput{progress: cmd.ErrOrStderr(), report: cmd.OutOrStdout()}, nil
+	default:
+		return runOutput{}, fmt.Errorf("Unknown output format '%s', use 'text' or 'json'.", outputFormat)
+	}
+}
+
+// printReport shows the outcome of every applied action as a table, or as JSON with --output json
+func printReport(reportOutput io.Writer, report *markdown.ApplyReport) error {
+	if outputFormat == outputJSON {
+		encoder := json.NewEncoder(reportOutput)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("Error writing report: %w", err)
+		}
+		return nil
+	}
+
+	if len(report.Results) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(reportOutput, "\nSummary:")
+	writer := tabwriter.NewWriter(reportOutput, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "  STATUS\tID\tDURATION\tCHANGE")
+	for _, result := range report.Results {
+		objectID := result.ObjectID
+		if objectID == "" {
+			objectID = "-"
+		}
+		duration := time.Duration(result.DurationMs) * time.Millisecond
+		fmt.Fprintf(writer, "  %s\t%s\t%s\t%s\n", strings.ReplaceAll(string(result.Status), "_", " "), objectID, duration, result.Description)
+	}
+	writer.Flush()
+
+	for _, result := range report.Results {
+		if result.Error != "" {
+			fmt.Fprintf(reportOutput, "  ! %s: %s\n", result.Description, result.Error)
+		}
+	}
+
+	fmt.Fprintf(reportOutput, "\n%d applied, %d failed, %d skipped, %d rolled back.\n", report.Count(markdown.JournalApplied),
+		report.Count(markdown.JournalFailed), report.Count(markdown.JournalSkipped), report.Count(markdown.JournalRolledBack))
+	return nil
+}
+
+// finishReport prints the report once the command is done and makes sure a run with failed actions exits non-zero
+func finishReport(state *boardState, err error) error {
+	if reportErr := printReport(state.output.report, state.report); reportErr != nil && err == nil {
+		err = reportErr
+	}
+	if failedCount := state.report.Count(markdown.JournalFailed); err == nil && failedCount > 0 {
+		err = fmt.Errorf("%d change(s) failed.", failedCount)
+	}
+	return err
+}
@@ -1,11 +1,13 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/vinzmyko/mdello/config"
+	"github.com/vinzmyko/mdello/trello"
 )
 
 var rootCmd = &cobra.Command{
@@ -19,17 +21,85 @@ var rootCmd = &cobra.Command{
 
 var apiKey string
 var cfg *config.Config
+var verbose bool
 
-func Execute(trelloAPIKey string, configuration *config.Config) {
-	apiKey = trelloAPIKey
+// Global flags, they take precedence over the environment and the config file
+var (
+	boardFlag   string
+	configFlag  string
+	profileFlag string
+)
+
+// loadConfig runs before every command, once the flags are parsed
+func loadConfig(cmd *cobra.Command, args []string) error {
+	if configFlag != "" {
+		config.SetPath(configFlag)
+	}
+
+	configuration, err := config.Load(profileFlag)
+	if errors.Is(err, config.ErrProfileNotFound) {
+		return fmt.Errorf("Error loading configuration: %w. Run 'mdello profile list' to see your profiles.", err)
+	}
+	if err != nil {
+		return fmt.Errorf("Error loading configuration: %w", err)
+	}
+	if boardFlag != "" {
+		configuration.CurrentBoardID = boardFlag
+	}
 	cfg = configuration
+	return nil
+}
+
+// newTrelloClient creates a client that writes its verbose output to stderr when --verbose is set. MDELLO_API_URL
+// points it at another Trello compatible API, e.g. a fake server for offline development
+func newTrelloClient(token string) (*trello.TrelloClient, error) {
+	baseURL := os.Getenv("MDELLO_API_URL")
+	if baseURL == "" {
+		baseURL = trello.DefaultBaseURL
+	}
+
+	trelloClient, err := trello.NewTrelloClientWithURL(baseURL, apiKey, token)
+	if err != nil {
+		return nil, err
+	}
 
+	if verbose {
+		trelloClient.SetLogger(func(format string, args ...any) {
+			fmt.Fprintf(os.Stderr, format, args...)
+		})
+	}
+	return trelloClient, nil
+}
+
+// newConfiguredClient creates a client with the token from the config's credential source
+func newConfiguredClient() (*trello.TrelloClient, error) {
+	token, err := trelloToken()
+	if err != nil {
+		return nil, err
+	}
+	return newTrelloClient(token)
+}
+
+func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	// Commands return their errors, Execute prints them once and sets the exit code
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Print details such as retried requests")
+	rootCmd.PersistentFlags().StringVar(&boardFlag, "board", "", "ID of the board to use instead of the current board")
+	rootCmd.PersistentFlags().StringVar(&configFlag, "config", "", "Config file to use instead of ~/.mdello/config.json")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Profile to use instead of the current profile")
+	rootCmd.PersistentPreRunE = loadConfig
 
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(boardsCmd)
 	rootCmd.AddCommand(boardCmd)
 	rootCmd.AddCommand(openCmd)
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(configCmd)
 
 	rootCmd.SetUsageTemplate(
 		`Usage:
@@ -56,8 +126,23 @@ Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
 Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
 `)
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+}
+
+// execute runs the command and returns its exit code. Errors go to stderr, so stdout only ever holds what the
+// command writes, e.g. the markdown of 'mdello pull > board.md' or the report of --output json
+func execute() int {
+	err := rootCmd.Execute()
+	if err == nil {
+		return 0
+	}
+	message, exitCode := describeError(err)
+	fmt.Fprintln(rootCmd.ErrOrStderr(), message)
+	return exitCode
+}
+
+func Execute(trelloAPIKey string) {
+	apiKey = trelloAPIKey
+	if exitCode := execute(); exitCode != 0 {
+		os.Exit(exitCode)
 	}
 }
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vinzmyko/mdello/markdown"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last changes made to current board",
+	Long:  "Undo the changes applied to the current board by the last 'mdello board' or 'mdello apply' run. Running it again undoes the run before that.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cfg.HasCredentials() || cfg.CurrentBoardID == "" {
+			return errors.New("No valid cfg found. Please run 'mdello init'.")
+		}
+
+		out := cmd.OutOrStdout()
+		journal, err := markdown.LoadLatestJournal(cfg.CurrentBoardID)
+		if errors.Is(err, markdown.ErrNoJournal) {
+			fmt.Fprintln(out, "No changes to undo.")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Error reading journal: %w", err)
+		}
+
+		inverses, irreversible, err := journal.Inverses()
+		if err != nil {
+			return fmt.Errorf("Error reading journal: %w", err)
+		}
+
+		fmt.Fprintf(out, "Undoing changes from %s\n", journal.StartedAt.Local().Format("02 Jan 2006 15:04"))
+		if len(inverses) > 0 {
+			printPlan(out, inverses)
+		}
+		if len(irreversible) > 0 {
+			fmt.Fprintln(out, "\nThese changes cannot be undone:")
+			for _, description := range irreversible {
+				fmt.Fprintf(out, "  ! %s\n", description)
+			}
+		}
+		if len(inverses) == 0 {
+			return nil
+		}
+
+		if confirmChanges(out, len(inverses)) != confirmYes {
+			fmt.Fprintln(out, "No changes applied.")
+			return nil
+		}
+
+		trelloClient, err := newConfiguredClient()
+		if err != nil {
+			return fmt.Errorf("Error creating trello client: %w", err)
+		}
+
+		if err := journal.Rollback(trelloClient, out); err != nil {
+			return fmt.Errorf("Undo incomplete: %w", err)
+		}
+
+		fmt.Fprintln(out, "\nUndo complete!")
+		return nil
+	},
+}
@@ -1,10 +1,15 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/vinzmyko/mdello/trello"
 )
@@ -20,25 +25,100 @@ type DateFormatOption struct {
 	Value   string
 }
 
+// Overrides from the environment, see Load for the order they are applied in
+const (
+	EnvHome       = "MDELLO_HOME"
+	EnvConfig     = "MDELLO_CONFIG"
+	EnvProfile    = "MDELLO_PROFILE"
+	EnvToken      = "MDELLO_TOKEN"
+	EnvBoard      = "MDELLO_BOARD"
+	EnvDateFormat = "MDELLO_DATE_FORMAT"
+)
+
+// DefaultProfile is the profile stored at the top level of the config file
+const DefaultProfile = "default"
+
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Profile holds the settings for one Trello account
+type Profile struct {
+	Token          string `json:"token,omitempty"`
+	TokenCommand   string `json:"token_command,omitempty"` // Prints the token, e.g. pass show trello
+	TokenFile      string `json:"token_file,omitempty"`    // Token encrypted with a passphrase
+	CurrentBoardID string `json:"default_board,omitempty"`
+	DateFormat     string `json:"date_format,omitempty"`
+	Editor         string `json:"editor,omitempty"`        // Command to edit boards with, git's editor when empty
+	LabelDisplay   string `json:"label_display,omitempty"` // LabelDisplayNames when empty
+	TimeZone       string `json:"time_zone,omitempty"`     // IANA name for due dates, the system's when empty
+}
+
+// Config holds the active profile's settings. In the file the default profile is kept at the top level, so config
+// files from before profiles still load, and the others under profiles.
+//
+// Fields changed directly, e.g. by an environment variable or flag, only last for the run. Use the Update methods
+// for changes that should be saved
 type Config struct {
-	Token          string `json:"token"`
-	CurrentBoardID string `json:"currentBoardId"`
-	DateFormat string `json:"DateFormat"`
+	Version int `json:"version"` // See schemaVersion
+	Profile
+	Profiles       map[string]*Profile `json:"profiles,omitempty"`
+	CurrentProfile string              `json:"current_profile,omitempty"` // Profile used when none is given
+
+	profileName string
+	persisted   *Config // The values read from the file, what Save writes back
 }
 
-func SaveConfig(config Config) error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("could not find home directory: %w", err)
+// pathOverride is the config file given on the command line
+var pathOverride string
+
+// SetPath makes Load and SaveConfig use path, it takes precedence over MDELLO_CONFIG
+func SetPath(path string) {
+	pathOverride = path
+}
+
+// ConfigDir returns the ~/.mdello directory, or MDELLO_HOME when it is set, creating it if it does not exist
+func ConfigDir() (string, error) {
+	configDir := os.Getenv(EnvHome)
+	if configDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not find home directory: %w", err)
+		}
+		configDir = filepath.Join(homeDir, ".mdello")
 	}
 
-	configDir := filepath.Join(homeDir, ".mdello")
 	if err := os.MkdirAll(configDir, 0700); err != nil { // 0700 = owner read/write/execute only, only current user can access dir
-		return fmt.Errorf("could not create config directory: %w", err)
+		return "", fmt.Errorf("could not create config directory: %w", err)
 	}
 
-	configFile := filepath.Join(configDir, "config.json")
+	return configDir, nil
+}
 
+// Path returns the config file to use: the one set with SetPath, then MDELLO_CONFIG, then config.json in ConfigDir
+func Path() (string, error) {
+	if pathOverride != "" {
+		return pathOverride, nil
+	}
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path, nil
+	}
+
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "config.json"), nil
+}
+
+func SaveConfig(config Config) error {
+	configFile, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(configFile), 0700); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	config.Version = schemaVersion
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("could not marshal config: %w", err)
@@ -48,16 +128,19 @@ func SaveConfig(config Config) error {
 }
 
 func LoadConfig() (*Config, error) {
-	homeDir, err := os.UserHomeDir()
+	configFile, err := Path()
 	if err != nil {
-		return nil, fmt.Errorf("could not find home directory: %w", err)
+		return nil, err
 	}
 
-	configFile := filepath.Join(homeDir, ".mdello", "config.json")
 	data, err := os.ReadFile(configFile)
 	if err != nil {
 		return nil, fmt.Errorf("could not read config file: %w", err)
 	}
+	data, err = migrateFile(configFile, data)
+	if err != nil {
+		return nil, err
+	}
 
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
@@ -67,27 +150,218 @@ func LoadConfig() (*Config, error) {
 	return &config, nil
 }
 
-func (cfg *Config) GetCurrentBoard(trelloClient *trello.TrelloClient) (*trello.Board, error) {
+// ParseConfig reads the contents of a config file, upgrading older versions. Unlike LoadConfig it rejects unknown
+// keys and invalid settings, for checking a file edited by hand
+func ParseConfig(data []byte) (*Config, error) {
+	migrated, _, err := migrate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	decoder := json.NewDecoder(bytes.NewReader(migrated))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("could not read config: %w", err)
+	}
+
+	if err := config.normaliseSettings(); err != nil {
+		return nil, err
+	}
+	if config.CurrentProfile != "" && config.profile(config.CurrentProfile) == nil {
+		return nil, fmt.Errorf("current_profile: %w: %s", ErrProfileNotFound, config.CurrentProfile)
+	}
+	return &config, nil
+}
+
+// Load reads the config file, a missing file gives an empty config, and selects a profile: profileName when it is
+// set, then MDELLO_PROFILE, then the file's current profile. The MDELLO_* environment variables are applied on top
+// of the profile. Later sources win: the config file, then environment variables, then flags set by the caller
+func Load(profileName string) (*Config, error) {
+	file, err := LoadConfig()
+	if errors.Is(err, fs.ErrNotExist) {
+		file, err = &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if profileName == "" {
+		profileName = os.Getenv(EnvProfile)
+	}
+	if profileName == "" {
+		profileName = file.CurrentProfile
+	}
+	if profileName == "" {
+		profileName = DefaultProfile
+	}
+	profile := file.profile(profileName)
+	if profile == nil {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, profileName)
+	}
+
+	config := &Config{
+		Profile:        *profile,
+		Profiles:       file.Profiles,
+		CurrentProfile: file.CurrentProfile,
+		profileName:    profileName,
+		persisted:      file,
+	}
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// profile returns the stored profile called name, or nil
+func (cfg *Config) profile(name string) *Profile {
+	if name == DefaultProfile {
+		return &cfg.Profile
+	}
+	return cfg.Profiles[name]
+}
+
+// stored returns what Save writes, the file as it was loaded with the changes made through the Update methods
+func (cfg *Config) stored() *Config {
+	if cfg.persisted == nil {
+		cfg.persisted = &Config{Profile: cfg.Profile, Profiles: cfg.Profiles, CurrentProfile: cfg.CurrentProfile}
+	}
+	return cfg.persisted
+}
+
+// ProfileName is the name of the active profile
+func (cfg *Config) ProfileName() string {
+	if cfg.profileName == "" {
+		return DefaultProfile
+	}
+	return cfg.profileName
+}
+
+// ProfileNames lists the default profile first, then the others by name
+func (cfg *Config) ProfileNames() []string {
+	names := make([]string, 0, len(cfg.stored().Profiles))
+	for name := range cfg.stored().Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...)
+}
+
+// LookupProfile returns the profile called name as it is stored, without overrides
+func (cfg *Config) LookupProfile(name string) (Profile, bool) {
+	profile := cfg.stored().profile(name)
+	if profile == nil {
+		return Profile{}, false
+	}
+	return *profile, true
+}
+
+// SetProfile adds or replaces the profile called name
+func (cfg *Config) SetProfile(name string, profile Profile) {
+	stored := cfg.stored()
+	if name == DefaultProfile {
+		stored.Profile = profile
+	} else {
+		if stored.Profiles == nil {
+			stored.Profiles = make(map[string]*Profile)
+		}
+		stored.Profiles[name] = &profile
+	}
+
+	if name == cfg.ProfileName() {
+		cfg.Profile = profile
+	}
+	cfg.Profiles = stored.Profiles
+}
+
+// RemoveProfile deletes a named profile, the default profile cannot be removed. If it was the current profile the
+// default one becomes current
+func (cfg *Config) RemoveProfile(name string) error {
+	if name == DefaultProfile {
+		return errors.New("the default profile cannot be removed")
+	}
+	stored := cfg.stored()
+	if stored.profile(name) == nil {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	delete(stored.Profiles, name)
+	if stored.CurrentProfile == name {
+		stored.CurrentProfile = ""
+	}
+	cfg.CurrentProfile = stored.CurrentProfile
+	return nil
+}
+
+// UseProfile makes name the profile used when none is given
+func (cfg *Config) UseProfile(name string) error {
+	stored := cfg.stored()
+	if stored.profile(name) == nil {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	stored.CurrentProfile = name
+	if name == DefaultProfile {
+		stored.CurrentProfile = ""
+	}
+	cfg.CurrentProfile = stored.CurrentProfile
+	return nil
+}
+
+func (cfg *Config) applyEnv() error {
+	if token := os.Getenv(EnvToken); token != "" {
+		// A token from the environment replaces every other credential source
+		cfg.Token = token
+		cfg.TokenCommand = ""
+		cfg.TokenFile = ""
+	}
+	if boardID := os.Getenv(EnvBoard); boardID != "" {
+		cfg.CurrentBoardID = boardID
+	}
+	if value := os.Getenv(EnvDateFormat); value != "" {
+		dateFormat, found := LookupDateFormat(value)
+		if !found {
+			return fmt.Errorf("%s '%s' is not a date format, use iso, us or eu", EnvDateFormat, value)
+		}
+		cfg.DateFormat = dateFormat
+	}
+	return nil
+}
+
+func (cfg *Config) GetCurrentBoard(trelloClient trello.API) (*trello.Board, error) {
 	if cfg.CurrentBoardID == "" {
 		return nil, fmt.Errorf("no current board set")
 	}
 	return trelloClient.GetBoard(cfg.CurrentBoardID)
 }
 
+func (cfg *Config) GetCurrentBoardSnapshot(trelloClient trello.API) (*trello.BoardSnapshot, error) {
+	if cfg.CurrentBoardID == "" {
+		return nil, fmt.Errorf("no current board set")
+	}
+	return trelloClient.GetBoardSnapshot(cfg.CurrentBoardID)
+}
+
+// The Update methods change the active profile for this run and in the file
+
 func (cfg *Config) UpdateToken(newToken string) {
 	cfg.Token = newToken
+	cfg.stored().profile(cfg.ProfileName()).Token = newToken
 }
 
 func (cfg *Config) UpdateBoardID(newBoardID string) {
 	cfg.CurrentBoardID = newBoardID
+	cfg.stored().profile(cfg.ProfileName()).CurrentBoardID = newBoardID
 }
 
 func (cfg *Config) UpdateDateFormat(newDateFormat string) {
 	cfg.DateFormat = newDateFormat
+	cfg.stored().profile(cfg.ProfileName()).DateFormat = newDateFormat
 }
 
+// Save writes the config back to its file without the overrides from the environment and flags
 func (cfg *Config) Save() error {
-	return SaveConfig(*cfg)
+	return SaveConfig(*cfg.stored())
 }
 
 func GetDateFormatOptions() []DateFormatOption {
@@ -98,6 +372,20 @@ func GetDateFormatOptions() []DateFormatOption {
 	}
 }
 
+// LookupDateFormat accepts a date format by name, iso, us or eu, or as one of the layouts themselves
+func LookupDateFormat(value string) (string, bool) {
+	names := map[string]string{"iso": DateFormatISO, "us": DateFormatUS, "eu": DateFormatEU}
+	if dateFormat, found := names[strings.ToLower(value)]; found {
+		return dateFormat, true
+	}
+	for _, option := range GetDateFormatOptions() {
+		if option.Value == value {
+			return value, true
+		}
+	}
+	return "", false
+}
+
 func GetDisplayOptions() []string {
 	options := GetDateFormatOptions()
 	displays := make([]string, len(options))
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// The token can come from three places, checked in this order: token_command, token_file and the plain text token
+// field. Only the plain text field stores the token in config.json
+
+const (
+	tokenFileVersion    = 1
+	tokenFileIterations = 600000 // OWASP's recommendation for PBKDF2-HMAC-SHA256
+	tokenFileKDF        = "pbkdf2-sha256"
+
+	// The iteration count is read from the file, these bounds keep a crafted file from weakening the key
+	// derivation or hanging startup
+	tokenFileMinIterations = tokenFileIterations
+	tokenFileMaxIterations = 10 * tokenFileIterations
+)
+
+var ErrWrongPassphrase = errors.New("wrong passphrase or the token file is damaged")
+
+type encryptedTokenFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// HasCredentials reports whether the config says where to get a token from, without resolving it
+func (cfg *Config) HasCredentials() bool {
+	return cfg != nil && (cfg.Token != "" || cfg.TokenCommand != "" || cfg.TokenFile != "")
+}
+
+// TokenSource names the config key the profile's token comes from
+func (p *Profile) TokenSource() string {
+	switch {
+	case p.TokenCommand != "":
+		return "token_command"
+	case p.TokenFile != "":
+		return "token_file"
+	case p.Token != "":
+		return "token"
+	default:
+		return "none"
+	}
+}
+
+// ResolveToken returns the token from whichever source is configured. passphrase is only called for an encrypted
+// token file
+func (p *Profile) ResolveToken(passphrase func() (string, error)) (string, error) {
+	switch {
+	case p.TokenCommand != "":
+		return runTokenCommand(p.TokenCommand)
+	case p.TokenFile != "":
+		secret, err := passphrase()
+		if err != nil {
+			return "", err
+		}
+		return DecryptTokenFile(p.TokenFile, secret)
+	case p.Token != "":
+		return p.Token, nil
+	default:
+		return "", errors.New("no token configured")
+	}
+}
+
+// runTokenCommand runs command through the shell like a git credential helper, the token is the first line it
+// prints. The command can prompt on the terminal, e.g. for a GPG passphrase
+func runTokenCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	var stdout bytes.Buffer
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("token_command '%s' failed: %w", command, err)
+	}
+
+	token, _, _ := strings.Cut(stdout.String(), "\n")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("token_command '%s' did not print a token", command)
+	}
+	return token, nil
+}
+
+// DefaultTokenFile is where init stores a profile's encrypted token
+func DefaultTokenFile(profileName string) (string, error) {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if profileName == DefaultProfile {
+		return filepath.Join(configDir, "token.enc"), nil
+	}
+	return filepath.Join(configDir, fmt.Sprintf("token-%s.enc", profileName)), nil
+}
+
+// EncryptTokenFile writes token to path encrypted with AES-256-GCM, using a key derived from passphrase
+func EncryptTokenFile(path, token, passphrase string) error {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("could not generate salt: %w", err)
+	}
+
+	gcm, err := tokenCipher(passphrase, salt, tokenFileIterations)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("could not generate nonce: %w", err)
+	}
+
+	file := encryptedTokenFile{
+		Version:    tokenFileVersion,
+		KDF:        tokenFileKDF,
+		Iterations: tokenFileIterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, []byte(token), nil),
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal token file: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+func DecryptTokenFile(path, passphrase string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read token file: %w", err)
+	}
+
+	var file encryptedTokenFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return "", fmt.Errorf("could not unmarshal token file: %w", err)
+	}
+	if file.Version != tokenFileVersion || file.KDF != tokenFileKDF {
+		return "", fmt.Errorf("unsupported token file version %d", file.Version)
+	}
+	if file.Iterations < tokenFileMinIterations || file.Iterations > tokenFileMaxIterations {
+		return "", fmt.Errorf("token file has %d key derivation iterations, expected %d to %d", file.Iterations, tokenFileMinIterations, tokenFileMaxIterations)
+	}
+
+	gcm, err := tokenCipher(passphrase, file.Salt, file.Iterations)
+	if err != nil {
+		return "", err
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		return "", ErrWrongPassphrase
+	}
+	token, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+	return string(token), nil
+}
+
+func tokenCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
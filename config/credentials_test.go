@@ -0,0 +1,54 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTokenFileKeepsThePassphraseAsTyped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.enc")
+	if err := EncryptTokenFile(path, "secret-token", "  padded passphrase "); err != nil {
+		t.Fatalf("EncryptTokenFile() failed: %v", err)
+	}
+
+	token, err := DecryptTokenFile(path, "  padded passphrase ")
+	if err != nil || token != "secret-token" {
+		t.Fatalf("DecryptTokenFile() = %q, %v, want the token", token, err)
+	}
+	if _, err := DecryptTokenFile(path, "padded passphrase"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("DecryptTokenFile() with the trimmed passphrase = %v, want ErrWrongPassphrase", err)
+	}
+}
+
+func TestDecryptTokenFileBoundsTheIterations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.enc")
+	if err := EncryptTokenFile(path, "secret-token", "passphrase"); err != nil {
+		t.Fatalf("EncryptTokenFile() failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading the token file failed: %v", err)
+	}
+	var file encryptedTokenFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("parsing the token file failed: %v", err)
+	}
+
+	for _, iterations := range []int{0, 1, tokenFileMinIterations - 1, tokenFileMaxIterations + 1, 1 << 40} {
+		file.Iterations = iterations
+		data, err := json.Marshal(file)
+		if err != nil {
+			t.Fatalf("encoding the token file failed: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatalf("writing the token file failed: %v", err)
+		}
+
+		if _, err := DecryptTokenFile(path, "passphrase"); err == nil {
+			t.Errorf("a token file with %d iterations was accepted", iterations)
+		}
+	}
+}
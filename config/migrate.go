@@ -0,0 +1,106 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// schemaVersion is the version of the config file this build writes. Each change to the file's layout adds a
+// migration that upgrades files from the version before it
+const schemaVersion = 1
+
+type migration func(file map[string]any) error
+
+// migrations[i] upgrades a file from version i to version i+1
+var migrations = []migration{
+	migrateToSnakeCase,
+}
+
+// Version 0 files have no version field and mixed case keys. The README also documented trello_token and
+// current_board, so those are accepted too
+func migrateToSnakeCase(file map[string]any) error {
+	renames := map[string]string{
+		"currentBoardId": "default_board",
+		"DateFormat":     "date_format",
+		"trello_token":   "token",
+		"current_board":  "default_board",
+	}
+
+	renameKeys := func(profile map[string]any) {
+		for oldKey, newKey := range renames {
+			value, found := profile[oldKey]
+			if !found {
+				continue
+			}
+			delete(profile, oldKey)
+			if _, exists := profile[newKey]; !exists {
+				profile[newKey] = value
+			}
+		}
+	}
+
+	renameKeys(file)
+	if profiles, ok := file["profiles"].(map[string]any); ok {
+		for _, profile := range profiles {
+			if profile, ok := profile.(map[string]any); ok {
+				renameKeys(profile)
+			}
+		}
+	}
+	return nil
+}
+
+// migrate upgrades the contents of a config file to schemaVersion, it returns the version the file had
+func migrate(data []byte) ([]byte, int, error) {
+	var file map[string]any
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, 0, fmt.Errorf("could not unmarshal config: %w", err)
+	}
+
+	version := 0
+	if value, found := file["version"]; found {
+		number, ok := value.(float64)
+		if !ok || number < 0 || number != float64(int(number)) {
+			return nil, 0, fmt.Errorf("config version must be a whole number, got %v", value)
+		}
+		version = int(number)
+	}
+	if version > schemaVersion {
+		return nil, 0, fmt.Errorf("config file version %d was written by a newer version of mdello, which supports up to version %d", version, schemaVersion)
+	}
+	if version == schemaVersion {
+		return data, version, nil
+	}
+
+	for i := version; i < schemaVersion; i++ {
+		if err := migrations[i](file); err != nil {
+			return nil, 0, fmt.Errorf("could not migrate config from version %d: %w", i, err)
+		}
+	}
+	file["version"] = schemaVersion
+
+	migrated, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not marshal config: %w", err)
+	}
+	return migrated, version, nil
+}
+
+// migrateFile upgrades an older config file and writes it back, after keeping the original next to it as
+// config.json.v<version>.bak
+func migrateFile(path string, data []byte) ([]byte, error) {
+	migrated, version, err := migrate(data)
+	if err != nil || version == schemaVersion {
+		return migrated, err
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("could not back up config before migrating it: %w", err)
+	}
+	if err := os.WriteFile(path, migrated, 0600); err != nil {
+		return nil, fmt.Errorf("could not write migrated config: %w", err)
+	}
+	return migrated, nil
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[string]any
+	}{
+		{
+			name: "mixed case keys of version 0",
+			data: `{"token": "abc", "currentBoardId": "board", "DateFormat": "02-01-2006 15:04"}`,
+			want: map[string]any{"version": 1.0, "token": "abc", "default_board": "board", "date_format": "02-01-2006 15:04"},
+		},
+		{
+			name: "keys from the old README",
+			data: `{"trello_token": "abc", "current_board": "board"}`,
+			want: map[string]any{"version": 1.0, "token": "abc", "default_board": "board"},
+		},
+		{
+			name: "a new key is kept over an old one",
+			data: `{"currentBoardId": "old", "default_board": "new"}`,
+			want: map[string]any{"version": 1.0, "default_board": "new"},
+		},
+		{
+			name: "profiles are migrated too",
+			data: `{"token": "abc", "profiles": {"work": {"trello_token": "def", "currentBoardId": "board"}}}`,
+			want: map[string]any{
+				"version":  1.0,
+				"token":    "abc",
+				"profiles": map[string]any{"work": map[string]any{"token": "def", "default_board": "board"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			migrated, version, err := migrate([]byte(test.data))
+			if err != nil {
+				t.Fatalf("migrate() failed: %v", err)
+			}
+			if version != 0 {
+				t.Errorf("migrate() read version %d, want 0", version)
+			}
+
+			var got map[string]any
+			if err := json.Unmarshal(migrated, &got); err != nil {
+				t.Fatalf("the migrated config is not JSON: %v", err)
+			}
+			if gotJSON, wantJSON := mustJSON(t, got), mustJSON(t, test.want); gotJSON != wantJSON {
+				t.Errorf("migrate() = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestMigrateLeavesCurrentFilesAlone(t *testing.T) {
+	data := []byte(`{"version": 1, "token": "abc"}`)
+	migrated, version, err := migrate(data)
+	if err != nil {
+		t.Fatalf("migrate() failed: %v", err)
+	}
+	if version != schemaVersion || string(migrated) != string(data) {
+		t.Errorf("migrate() = %s, version %d, want the file unchanged", migrated, version)
+	}
+}
+
+func TestMigrateRejectsUnknownVersions(t *testing.T) {
+	for _, data := range []string{`{"version": 2}`, `{"version": 1.5}`, `{"version": "1"}`, `{"version": -1}`} {
+		if _, _, err := migrate([]byte(data)); err == nil {
+			t.Errorf("migrate(%s) did not fail", data)
+		}
+	}
+}
+
+func TestLoadMigratesVersion0AndKeepsABackup(t *testing.T) {
+	for _, env := range []string{EnvProfile, EnvToken, EnvBoard, EnvDateFormat} {
+		t.Setenv(env, "")
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv(EnvConfig, path)
+
+	original := []byte(`{"token": "abc", "currentBoardId": "board", "DateFormat": "01-02-2006 15:04"}`)
+	if err := os.WriteFile(path, original, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Token != "abc" || cfg.CurrentBoardID != "board" || cfg.DateFormat != DateFormatUS {
+		t.Errorf("Load() = %+v, want the settings of the version 0 file", cfg.Profile)
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil {
+		t.Fatalf("no backup was kept: %v", err)
+	}
+	if string(backup) != string(original) {
+		t.Errorf("backup = %s, want the original file", backup)
+	}
+
+	migrated, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(migrated), `"version": 1`) || strings.Contains(string(migrated), "currentBoardId") {
+		t.Errorf("the config file was not rewritten at version 1:\n%s", migrated)
+	}
+
+	// Loading the migrated file leaves it and the backup as they are
+	if _, err := Load(""); err != nil {
+		t.Fatalf("loading the migrated config failed: %v", err)
+	}
+	if again, _ := os.ReadFile(path); string(again) != string(migrated) {
+		t.Errorf("the migrated config was rewritten:\n%s", again)
+	}
+	if backups, _ := filepath.Glob(path + ".*.bak"); len(backups) != 1 {
+		t.Errorf("got backups %v, want only the version 0 one", backups)
+	}
+}
+
+func mustJSON(t *testing.T, value any) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// How card and board labels are shown in the markdown
+const (
+	LabelDisplayNames  = "names"
+	LabelDisplayHidden = "hidden" // Labels are left out and cannot be changed from the markdown
+)
+
+var ErrUnknownSetting = errors.New("unknown setting")
+
+var boardIDRegex = regexp.MustCompile(`^[0-9a-fA-F]{24}$`)
+
+// Setting is a profile preference that can be read and changed with mdello config
+type Setting struct {
+	Key         string
+	Description string
+	field       func(p *Profile) *string
+	normalise   func(value string) (string, error) // Validates a value and returns it as it is stored
+}
+
+var Settings = []Setting{
+	{
+		Key:         "date_format",
+		Description: "Format of due dates: iso, us or eu",
+		field:       func(p *Profile) *string { return &p.DateFormat },
+		normalise: func(value string) (string, error) {
+			dateFormat, found := LookupDateFormat(value)
+			if !found {
+				return "", fmt.Errorf("'%s' is not a date format, use iso, us or eu", value)
+			}
+			return dateFormat, nil
+		},
+	},
+	{
+		Key:         "editor",
+		Description: "Command to edit boards with, e.g. code --wait. Git's editor when empty",
+		field:       func(p *Profile) *string { return &p.Editor },
+		normalise:   func(value string) (string, error) { return value, nil },
+	},
+	{
+		Key:         "label_display",
+		Description: "How labels are shown in the markdown: names or hidden",
+		field:       func(p *Profile) *string { return &p.LabelDisplay },
+		normalise: func(value string) (string, error) {
+			if value != LabelDisplayNames && value != LabelDisplayHidden {
+				return "", fmt.Errorf("'%s' is not a label display, use %s or %s", value, LabelDisplayNames, LabelDisplayHidden)
+			}
+			return value, nil
+		},
+	},
+	{
+		Key:         "default_board",
+		Description: "ID of the board commands use when --board is not given",
+		field:       func(p *Profile) *string { return &p.CurrentBoardID },
+		normalise: func(value string) (string, error) {
+			if !boardIDRegex.MatchString(value) {
+				return "", fmt.Errorf("'%s' is not a board ID, board IDs are 24 hexadecimal characters", value)
+			}
+			return value, nil
+		},
+	},
+	{
+		Key:         "time_zone",
+		Description: "Time zone of due dates, e.g. Europe/London. The system's when empty",
+		field:       func(p *Profile) *string { return &p.TimeZone },
+		normalise: func(value string) (string, error) {
+			if _, err := time.LoadLocation(value); err != nil {
+				return "", fmt.Errorf("'%s' is not a time zone, use a name such as Europe/London", value)
+			}
+			return value, nil
+		},
+	},
+}
+
+func LookupSetting(key string) (Setting, error) {
+	for _, setting := range Settings {
+		if setting.Key == key {
+			return setting, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("%w '%s'", ErrUnknownSetting, key)
+}
+
+// Get returns the active profile's value for key, including overrides
+func (cfg *Config) Get(key string) (string, error) {
+	setting, err := LookupSetting(key)
+	if err != nil {
+		return "", err
+	}
+	return *setting.field(&cfg.Profile), nil
+}
+
+// Set validates value and stores it in the active profile. An empty value resets the setting to its default
+func (cfg *Config) Set(key, value string) error {
+	setting, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+
+	value = strings.TrimSpace(value)
+	if value != "" {
+		if value, err = setting.normalise(value); err != nil {
+			return err
+		}
+	}
+
+	*setting.field(&cfg.Profile) = value
+	*setting.field(cfg.stored().profile(cfg.ProfileName())) = value
+	return nil
+}
+
+// normaliseSettings checks every setting of every profile, e.g. after the file was edited by hand, and stores them
+// the way Set would
+func (cfg *Config) normaliseSettings() error {
+	var problems []string
+	for _, name := range cfg.ProfileNames() {
+		profile := cfg.profile(name)
+		if profile == nil { // "name": null in the file
+			continue
+		}
+		for _, setting := range Settings {
+			value := setting.field(profile)
+			if *value == "" {
+				continue
+			}
+			normalised, err := setting.normalise(*value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("profile %s, %s: %v", name, setting.Key, err))
+				continue
+			}
+			*value = normalised
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// DateLayout is the layout due dates are shown and read in, DateFormatISO when no date format is set
+func (p *Profile) DateLayout() string {
+	if p.DateFormat == "" {
+		return DateFormatISO
+	}
+	return p.DateFormat
+}
+
+// Location is the time zone due dates are shown and read in
+func (p *Profile) Location() *time.Location {
+	if p.TimeZone == "" {
+		return time.Local
+	}
+	location, err := time.LoadLocation(p.TimeZone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestSetDateFormatResetsToISO(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.DateLayout(); got != DateFormatISO {
+		t.Errorf("DateLayout() of a new config = %q, want %q", got, DateFormatISO)
+	}
+
+	if err := cfg.Set("date_format", "eu"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if got := cfg.DateLayout(); got != DateFormatEU {
+		t.Errorf("DateLayout() = %q, want %q", got, DateFormatEU)
+	}
+
+	if err := cfg.Set("date_format", ""); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if got := cfg.DateLayout(); got != DateFormatISO {
+		t.Errorf("DateLayout() after a reset = %q, want %q", got, DateFormatISO)
+	}
+}
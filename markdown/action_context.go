@@ -0,0 +1,95 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vinzmyko/mdello/trello"
+)
+
+// ActionContext is shared by every action applied in a session. Items created during the session only have a
+// sentinel ID in the markdown, the real trello IDs are recorded here so later actions can refer to them. Actions
+// applied in parallel share it, so every lookup holds mu
+type ActionContext struct {
+	BoardID    string
+	mu         sync.Mutex
+	createdIDs map[string]string // sentinel ID, or ID of a card recreated by a rollback -> trello ID
+	labelIDs   map[string]string // markdown label name -> trello ID, loaded on first use
+}
+
+func NewActionContext(boardID string) *ActionContext {
+	return &ActionContext{
+		BoardID:    boardID,
+		createdIDs: make(map[string]string),
+	}
+}
+
+// RecordCreatedID stores the trello ID of an item that was created for an ID. That is a sentinel ID, or when a
+// rollback recreates a deleted card the card's old ID, so older inverses that still refer to it find the new card
+func (ctx *ActionContext) RecordCreatedID(id, trelloID string) {
+	if id == "" {
+		return
+	}
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if ctx.createdIDs == nil {
+		ctx.createdIDs = make(map[string]string)
+	}
+	ctx.createdIDs[id] = trelloID
+}
+
+// ResolveID returns the trello ID for an ID from the markdown or the journal, sentinel IDs must have been created
+// earlier
+func (ctx *ActionContext) ResolveID(id string) (string, error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if trelloID, exists := ctx.createdIDs[id]; exists {
+		return trelloID, nil
+	}
+	if !IsSentinelID(id) {
+		return id, nil
+	}
+	return "", fmt.Errorf("%s has not been created yet", id)
+}
+
+// LabelID looks up a label by its markdown name, fetching the board labels once per session
+func (ctx *ActionContext) LabelID(t trello.API, labelName string) (string, error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if ctx.labelIDs == nil {
+		labels, err := t.GetBoardLabels(ctx.BoardID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get board labels: %w", err)
+		}
+
+		ctx.labelIDs = make(map[string]string)
+		for _, label := range labels {
+			ctx.labelIDs[markdownLabelName(label.Name)] = label.ID
+		}
+	}
+
+	labelID, exists := ctx.labelIDs[labelName]
+	if !exists {
+		return "", fmt.Errorf("label '%s' not found on board", labelName)
+	}
+	return labelID, nil
+}
+
+// setLabelID keeps the label cache in sync with labels created, renamed or deleted during the session
+func (ctx *ActionContext) setLabelID(labelName, labelID string) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if ctx.labelIDs == nil {
+		return // Not loaded yet, the next lookup fetches fresh labels
+	}
+	if labelID == "" {
+		delete(ctx.labelIDs, labelName)
+		return
+	}
+	ctx.labelIDs[labelName] = labelID
+}
+
+func markdownLabelName(labelName string) string {
+	return strings.ReplaceAll(labelName, " ", "~")
+}
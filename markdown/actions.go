@@ -3,6 +3,8 @@ package markdown
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/vinzmyko/mdello/config"
@@ -10,34 +12,40 @@ import (
 )
 
 type TrelloAction interface {
-	Apply(client *trello.TrelloClient, ctx *ActionContext) error
+	Apply(client trello.API, ctx *ActionContext) error
 	Description() string
+	// Inverse returns the action that undoes this one, it is called after Apply so created IDs can be resolved
+	Inverse(ctx *ActionContext) (TrelloAction, error)
 }
 
-type ActionContext struct {
-	BoardID string
-}
+var ErrNotReversible = errors.New("action cannot be undone")
 
-func CreateBulkUpdateAction(section DetailedTrelloAction, changedFields map[string]any, oldValues, newValues map[string]string) (TrelloAction, error) {
+func CreateBulkUpdateAction(section DetailedTrelloAction, changedFields, oldFields map[string]any, oldValues, newValues map[string]string) (TrelloAction, error) {
 	switch section.ObjectType {
 	case "board":
 		return DetailedUpdateBoardAction{
 			BoardID:       section.ObjectID,
+			Name:          section.ObjectName,
 			UpdatedFields: changedFields,
+			OldFields:     oldFields,
 			OldValues:     oldValues,
 			NewValues:     newValues,
 		}, nil
 	case "list":
 		return DetailedUpdateListAction{
 			ListID:        section.ObjectID,
+			Name:          section.ObjectName,
 			UpdatedFields: changedFields,
+			OldFields:     oldFields,
 			OldValues:     oldValues,
 			NewValues:     newValues,
 		}, nil
 	case "card":
 		return DetailedUpdateCardAction{
 			CardID:        section.ObjectID,
+			Name:          section.ObjectName,
 			UpdatedFields: changedFields,
+			OldFields:     oldFields,
 			OldValues:     oldValues,
 			NewValues:     newValues,
 		}, nil
@@ -46,15 +54,45 @@ func CreateBulkUpdateAction(section DetailedTrelloAction, changedFields map[stri
 	}
 }
 
+// formatPos converts a planned trello position for the API, items without one go to the bottom
+func formatPos(pos float64) string {
+	if pos <= 0 {
+		return "bottom"
+	}
+	return strconv.FormatFloat(pos, 'f', -1, 64)
+}
+
+// describeFieldChanges lists every field changed by a detailed edit in a stable order
+func describeFieldChanges(objectType, name string, oldValues, newValues map[string]string) string {
+	fieldNames := make([]string, 0, len(newValues))
+	for fieldName := range newValues {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+
+	changes := make([]string, 0, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		if fieldName == "Description" {
+			changes = append(changes, "Description updated")
+			continue
+		}
+		changes = append(changes, fmt.Sprintf(`%s changed from "%s" to "%s"`, fieldName, oldValues[fieldName], newValues[fieldName]))
+	}
+
+	return fmt.Sprintf(`%s "%s": %s`, objectType, name, strings.Join(changes, ", "))
+}
+
 // === BOARD ACTIONS ===
 type DetailedUpdateBoardAction struct {
 	BoardID       string
+	Name          string
 	UpdatedFields map[string]any
+	OldFields     map[string]any // API values before the update, used to undo it
 	OldValues     map[string]string
 	NewValues     map[string]string
 }
 
-func (detailedAct DetailedUpdateBoardAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (detailedAct DetailedUpdateBoardAction) Apply(t trello.API, ctx *ActionContext) error {
 	params := &trello.UpdateBoardParams{
 		ID: detailedAct.BoardID,
 	}
@@ -114,12 +152,28 @@ func (detailedAct DetailedUpdateBoardAction) Apply(t *trello.TrelloClient, ctx *
 		}
 	}
 
-	_, err := t.UpdateBoard(params)
-	return fmt.Errorf(`Failed to update board: %w`, err)
+	if _, err := t.UpdateBoard(params); err != nil {
+		return fmt.Errorf(`Failed to update board: %w`, err)
+	}
+	return nil
 }
 
 func (detailedAct DetailedUpdateBoardAction) Description() string {
-	return "" // Handled when we are doing individual fields
+	return describeFieldChanges("Board", detailedAct.Name, detailedAct.OldValues, detailedAct.NewValues)
+}
+
+func (detailedAct DetailedUpdateBoardAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	if len(detailedAct.OldFields) == 0 {
+		return nil, ErrNotReversible
+	}
+	return DetailedUpdateBoardAction{
+		BoardID:       detailedAct.BoardID,
+		Name:          detailedAct.Name,
+		UpdatedFields: detailedAct.OldFields,
+		OldFields:     detailedAct.UpdatedFields,
+		OldValues:     detailedAct.NewValues,
+		NewValues:     detailedAct.OldValues,
+	}, nil
 }
 
 type UpdateBoardNameAction struct {
@@ -128,60 +182,90 @@ type UpdateBoardNameAction struct {
 	NewName string
 }
 
-func (act UpdateBoardNameAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (act UpdateBoardNameAction) Apply(t trello.API, ctx *ActionContext) error {
 	params := &trello.UpdateBoardParams{
 		ID:   act.BoardID,
 		Name: &act.NewName,
 	}
 
-	_, err := t.UpdateBoard(params)
-	return fmt.Errorf(`Error updating board name: %w`, err)
+	if _, err := t.UpdateBoard(params); err != nil {
+		return fmt.Errorf(`Error updating board name: %w`, err)
+	}
+	return nil
 }
 
 func (act UpdateBoardNameAction) Description() string {
 	return fmt.Sprintf(`Board "%s" name changed from "%s" to "%s"`, act.BoardID, act.OldName, act.NewName)
 }
 
+func (act UpdateBoardNameAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateBoardNameAction{BoardID: act.BoardID, OldName: act.NewName, NewName: act.OldName}, nil
+}
+
 // === LABEL ACTIONS ===
 type CreateLabelAction struct {
+	LabelID string // Sentinel ID from the markdown
 	BoardID string
 	Name    string
 	Colour  string
 }
 
-func (act CreateLabelAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (act CreateLabelAction) Apply(t trello.API, ctx *ActionContext) error {
 	params := &trello.CreateLabelParams{
 		BoardID: act.BoardID,
 		Name:    act.Name,
 		Colour:  act.Colour,
 	}
-	_, err := t.CreateLabel(params)
-	return fmt.Errorf(`Error creating label: %w`, err)
+	label, err := t.CreateLabel(params)
+	if err != nil {
+		return fmt.Errorf(`Error creating label: %w`, err)
+	}
+
+	ctx.RecordCreatedID(act.LabelID, label.ID)
+	ctx.setLabelID(act.Name, label.ID)
+	return nil
 }
 
 func (act CreateLabelAction) Description() string {
 	return fmt.Sprintf(`Created label "%s" with colour "%s"`, act.Name, act.Colour)
 }
 
+func (act CreateLabelAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	labelID, err := ctx.ResolveID(act.LabelID)
+	if err != nil {
+		return nil, err
+	}
+	return DeleteLabelAction{ID: labelID, Name: act.Name, Colour: act.Colour}, nil
+}
+
 type UpdateLabelName struct {
 	ID      string
 	OldName string
 	NewName string
 }
 
-func (act UpdateLabelName) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (act UpdateLabelName) Apply(t trello.API, ctx *ActionContext) error {
 	params := &trello.UpdateLabelParams{
 		ID:   act.ID,
 		Name: &act.NewName,
 	}
-	_, err := t.UpdateLabel(params)
-	return fmt.Errorf(`Error updating label name: %w`, err)
+	if _, err := t.UpdateLabel(params); err != nil {
+		return fmt.Errorf(`Error updating label name: %w`, err)
+	}
+
+	ctx.setLabelID(act.OldName, "")
+	ctx.setLabelID(act.NewName, act.ID)
+	return nil
 }
 
 func (act UpdateLabelName) Description() string {
 	return fmt.Sprintf(`Label "%s" renamed to "%s"`, act.OldName, act.NewName)
 }
 
+func (act UpdateLabelName) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateLabelName{ID: act.ID, OldName: act.NewName, NewName: act.OldName}, nil
+}
+
 type UpdateLabelColour struct {
 	ID        string
 	Name      string
@@ -189,43 +273,61 @@ type UpdateLabelColour struct {
 	NewColour string
 }
 
-func (act UpdateLabelColour) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (act UpdateLabelColour) Apply(t trello.API, ctx *ActionContext) error {
 	params := &trello.UpdateLabelParams{
 		ID:     act.ID,
 		Colour: &act.NewColour,
 	}
-	_, err := t.UpdateLabel(params)
-	return fmt.Errorf(`Error updating label colour: %w`, err)
+	if _, err := t.UpdateLabel(params); err != nil {
+		return fmt.Errorf(`Error updating label colour: %w`, err)
+	}
+	return nil
 }
 
 func (act UpdateLabelColour) Description() string {
 	return fmt.Sprintf(`Label "%s" colour changed from "%s" to "%s"`, act.Name, act.OldColour, act.NewColour)
 }
 
+func (act UpdateLabelColour) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateLabelColour{ID: act.ID, Name: act.Name, OldColour: act.NewColour, NewColour: act.OldColour}, nil
+}
+
 type DeleteLabelAction struct {
-	ID   string
-	Name string
+	ID     string
+	Name   string
+	Colour string
 }
 
-func (act DeleteLabelAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
-	err := t.DeleteLabel(act.ID)
-	return fmt.Errorf(`Error deleting label: %w`, err)
+func (act DeleteLabelAction) Apply(t trello.API, ctx *ActionContext) error {
+	if err := t.DeleteLabel(act.ID); err != nil {
+		return fmt.Errorf(`Error deleting label: %w`, err)
+	}
+
+	ctx.setLabelID(act.Name, "")
+	return nil
 }
 
 func (act DeleteLabelAction) Description() string {
 	return fmt.Sprintf(`Label "%s" deleted`, act.Name)
 }
 
+// The label is recreated with the same name and colour, trello does not keep the cards it was on
+func (act DeleteLabelAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return CreateLabelAction{BoardID: ctx.BoardID, Name: act.Name, Colour: act.Colour}, nil
+}
+
 // === LIST ACTIONS ===
 
 type DetailedUpdateListAction struct {
 	ListID        string
+	Name          string
 	UpdatedFields map[string]any
+	OldFields     map[string]any // API values before the update, used to undo it
 	OldValues     map[string]string
 	NewValues     map[string]string
 }
 
-func (detailedAct DetailedUpdateListAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (detailedAct DetailedUpdateListAction) Apply(t trello.API, ctx *ActionContext) error {
 	params := &trello.UpdateListParams{
 		ID: detailedAct.ListID,
 	}
@@ -253,36 +355,68 @@ func (detailedAct DetailedUpdateListAction) Apply(t *trello.TrelloClient, ctx *A
 		}
 	}
 
-	_, err := t.UpdateList(params)
-	return fmt.Errorf(`Failed to update detailed information of list: %w`, err)
+	if _, err := t.UpdateList(params); err != nil {
+		return fmt.Errorf(`Failed to update detailed information of list: %w`, err)
+	}
+	return nil
 }
 
 func (detailedAct DetailedUpdateListAction) Description() string {
-	return ""
+	return describeFieldChanges("List", detailedAct.Name, detailedAct.OldValues, detailedAct.NewValues)
+}
+
+func (detailedAct DetailedUpdateListAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	if len(detailedAct.OldFields) == 0 {
+		return nil, ErrNotReversible
+	}
+	return DetailedUpdateListAction{
+		ListID:        detailedAct.ListID,
+		Name:          detailedAct.Name,
+		UpdatedFields: detailedAct.OldFields,
+		OldFields:     detailedAct.UpdatedFields,
+		OldValues:     detailedAct.NewValues,
+		NewValues:     detailedAct.OldValues,
+	}, nil
 }
 
 type CreateListAction struct {
+	ListID   string // Sentinel ID from the markdown
 	BoardID  string
 	Name     string
 	Position int
+	Pos      float64 // Trello position
 }
 
-func (act CreateListAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
-	posStr := fmt.Sprintf("%d.0", act.Position)
+func (act CreateListAction) Apply(t trello.API, ctx *ActionContext) error {
+	posStr := formatPos(act.Pos)
 
 	params := &trello.CreateListParams{
 		IdBoard: act.BoardID,
 		Name:    act.Name,
 		Pos:     &posStr,
 	}
-	_, err := t.CreateList(params)
-	return fmt.Errorf(`Error failed to create list action: %w`, err)
+	list, err := t.CreateList(params)
+	if err != nil {
+		return fmt.Errorf(`Error failed to create list action: %w`, err)
+	}
+
+	ctx.RecordCreatedID(act.ListID, list.ID)
+	return nil
 }
 
 func (act CreateListAction) Description() string {
 	return fmt.Sprintf(`Created list "%s" at position %d`, act.Name, act.Position)
 }
 
+// Trello lists cannot be deleted, so a created list is archived instead
+func (act CreateListAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	listID, err := ctx.ResolveID(act.ListID)
+	if err != nil {
+		return nil, err
+	}
+	return ArchiveListAction{ListID: listID, Name: act.Name, Value: true}, nil
+}
+
 // Need to handle due date, labels after we see it working
 type UpdateListNameAction struct {
 	ListID  string
@@ -290,55 +424,77 @@ type UpdateListNameAction struct {
 	NewName string
 }
 
-func (act UpdateListNameAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (act UpdateListNameAction) Apply(t trello.API, ctx *ActionContext) error {
 	params := &trello.UpdateListParams{
 		ID:   act.ListID,
 		Name: &act.NewName,
 	}
-	_, err := t.UpdateList(params)
-	return fmt.Errorf(`Error failed to update list name: %w`, err)
+	if _, err := t.UpdateList(params); err != nil {
+		return fmt.Errorf(`Error failed to update list name: %w`, err)
+	}
+	return nil
 }
 
 func (act UpdateListNameAction) Description() string {
 	return fmt.Sprintf(`List "%s" renamed to "%s"`, act.OldName, act.NewName)
 }
 
+func (act UpdateListNameAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateListNameAction{ListID: act.ListID, OldName: act.NewName, NewName: act.OldName}, nil
+}
+
 type UpdateListPositionAction struct {
 	ListID      string
 	Name        string
 	OldPosition int
 	NewPosition int
+	OldPos      float64 // Trello positions, the markdown positions above are only for descriptions
+	Pos         float64
 }
 
-func (act UpdateListPositionAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
-	posStr := fmt.Sprintf("%d.0", act.NewPosition)
+func (act UpdateListPositionAction) Apply(t trello.API, ctx *ActionContext) error {
+	posStr := formatPos(act.Pos)
 
 	params := &trello.UpdateListParams{
 		ID:  act.ListID,
 		Pos: &posStr,
 	}
-	_, err := t.UpdateList(params)
-	return fmt.Errorf(`Error failed to update list position: %w`, err)
+	if _, err := t.UpdateList(params); err != nil {
+		return fmt.Errorf(`Error failed to update list position: %w`, err)
+	}
+	return nil
 }
 
 func (act UpdateListPositionAction) Description() string {
 	return fmt.Sprintf(`List "%s" moved from position %d to %d`, act.Name, act.OldPosition, act.NewPosition)
 }
 
+func (act UpdateListPositionAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateListPositionAction{
+		ListID:      act.ListID,
+		Name:        act.Name,
+		OldPosition: act.NewPosition,
+		NewPosition: act.OldPosition,
+		OldPos:      act.Pos,
+		Pos:         act.OldPos,
+	}, nil
+}
+
 type ArchiveListAction struct {
 	ListID string
 	Name   string
 	Value  bool
 }
 
-func (act ArchiveListAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
-	val := false
+func (act ArchiveListAction) Apply(t trello.API, ctx *ActionContext) error {
 	params := &trello.ArchiveListParams{
 		ID:    act.ListID,
-		Value: &val,
+		Value: &act.Value,
+	}
+	if _, err := t.ArchiveList(params); err != nil {
+		return fmt.Errorf(`Error failed to archive list: %w`, err)
 	}
-	_, err := t.ArchiveList(params)
-	return fmt.Errorf(`Error failed to archive list: %w`, err)
+	return nil
 }
 
 func (act ArchiveListAction) Description() string {
@@ -349,16 +505,22 @@ func (act ArchiveListAction) Description() string {
 	}
 }
 
+func (act ArchiveListAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return ArchiveListAction{ListID: act.ListID, Name: act.Name, Value: !act.Value}, nil
+}
+
 // === CARD ACTIONS ===
 
 type DetailedUpdateCardAction struct {
 	CardID        string
+	Name          string
 	UpdatedFields map[string]any
+	OldFields     map[string]any // API values before the update, used to undo it
 	OldValues     map[string]string
 	NewValues     map[string]string
 }
 
-func (detailedAct DetailedUpdateCardAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (detailedAct DetailedUpdateCardAction) Apply(t trello.API, ctx *ActionContext) error {
 	params := &trello.UpdateCardParams{
 		ID: detailedAct.CardID,
 	}
@@ -402,131 +564,260 @@ func (detailedAct DetailedUpdateCardAction) Apply(t *trello.TrelloClient, ctx *A
 		}
 	}
 
-	_, err := t.UpdateCard(params)
-	return fmt.Errorf(`Error failed to update detailed card params: %w`, err)
+	if _, err := t.UpdateCard(params); err != nil {
+		return fmt.Errorf(`Error failed to update detailed card params: %w`, err)
+	}
+	return nil
 }
 
 func (detailedAct DetailedUpdateCardAction) Description() string {
-	return ""
+	return describeFieldChanges("Card", detailedAct.Name, detailedAct.OldValues, detailedAct.NewValues)
+}
+
+func (detailedAct DetailedUpdateCardAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	if len(detailedAct.OldFields) == 0 {
+		return nil, ErrNotReversible
+	}
+	return DetailedUpdateCardAction{
+		CardID:        detailedAct.CardID,
+		Name:          detailedAct.Name,
+		UpdatedFields: detailedAct.OldFields,
+		OldFields:     detailedAct.UpdatedFields,
+		OldValues:     detailedAct.NewValues,
+		NewValues:     detailedAct.OldValues,
+	}, nil
 }
 
 type CreateCardAction struct {
+	CardID      string // Sentinel ID from the markdown
+	ListID      string
 	ListName    string
 	Name        string
 	Position    int
+	Pos         float64 // Trello position
 	IsCompleted bool
+	Labels      []string
+	Due         string
 }
 
-func (act CreateCardAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
-	lists, err := t.GetLists(ctx.BoardID)
+func (act CreateCardAction) Apply(t trello.API, ctx *ActionContext) error {
+	listID, err := ctx.ResolveID(act.ListID)
 	if err != nil {
-		return errors.New("Failed to get board lists")
-	}
-	pos := fmt.Sprintf("%d", act.Position)
-
-	var targetedListID string
-	for _, list := range lists {
-		if list.Name == act.ListName {
-			targetedListID = list.ID
-			break
-		}
-	}
-	if targetedListID == "" {
-		return fmt.Errorf(`List "%s" not found on board`, act.ListName)
+		return fmt.Errorf(`List "%s" not found on board: %w`, act.ListName, err)
 	}
+	pos := formatPos(act.Pos)
 
 	params := &trello.CreateCardParams{
-		IdList:      targetedListID,
+		IdList:      listID,
 		Name:        &act.Name,
 		Pos:         &pos,
 		DueComplete: &act.IsCompleted,
 	}
-	_, err = t.CreateCard(params)
-	return fmt.Errorf(`Error failed to create card: %w`, err)
+
+	if len(act.Labels) > 0 {
+		labelIDs := make([]string, 0, len(act.Labels))
+		for _, labelName := range act.Labels {
+			labelID, err := ctx.LabelID(t, labelName)
+			if err != nil {
+				return err
+			}
+			labelIDs = append(labelIDs, labelID)
+		}
+		params.IdLabels = &labelIDs
+	}
+
+	if act.Due != "" {
+		params.Due = &act.Due
+	}
+
+	card, err := t.CreateCard(params)
+	if err != nil {
+		return fmt.Errorf(`Error failed to create card: %w`, err)
+	}
+
+	ctx.RecordCreatedID(act.CardID, card.ID)
+	return nil
 }
 
 func (act CreateCardAction) Description() string {
 	return fmt.Sprintf(`Create card "%s" at position %d`, act.Name, act.Position)
 }
 
+func (act CreateCardAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return nil, err
+	}
+	if cardID == "" {
+		return nil, ErrNotReversible
+	}
+	listID, err := ctx.ResolveID(act.ListID)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeleteCardAction{
+		CardID:      cardID,
+		Name:        act.Name,
+		ListID:      listID,
+		ListName:    act.ListName,
+		Position:    act.Position,
+		Pos:         act.Pos,
+		IsCompleted: act.IsCompleted,
+		Labels:      act.Labels,
+		Due:         act.Due,
+	}, nil
+}
+
 type MoveCardAction struct {
-	CardID   string
-	Name     string
-	FromList string
-	ToList   string
-	Position int
+	CardID      string
+	Name        string
+	FromList    string
+	ToList      string
+	Position    int
+	OldPosition int
+	Pos         float64 // Trello positions in the list moved to and the list moved from
+	OldPos      float64
 }
 
-func (act MoveCardAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
-	posStr := fmt.Sprintf("%d.0", act.Position)
+func (act MoveCardAction) Apply(t trello.API, ctx *ActionContext) error {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return fmt.Errorf("card '%s' not found on board: %w", act.Name, err)
+	}
+
+	posStr := formatPos(act.Pos)
+
+	// The list the card is moved to may have been created in this session
+	toListID, err := ctx.ResolveID(act.ToList)
+	if err != nil {
+		return fmt.Errorf(`Error failed to resolve list for card "%s": %w`, act.Name, err)
+	}
 
 	params := &trello.UpdateCardParams{
-		ID:     act.CardID,
-		IdList: &act.ToList,
+		ID:     cardID,
+		IdList: &toListID,
 		Pos:    &posStr,
 	}
-	_, err := t.UpdateCard(params)
-	return fmt.Errorf(`Error failed to update move card action: %w`, err)
+	if _, err := t.UpdateCard(params); err != nil {
+		return fmt.Errorf(`Error failed to update move card action: %w`, err)
+	}
+	return nil
 }
 
 func (act MoveCardAction) Description() string {
 	return fmt.Sprintf(`Card "%s" moved from list "%s" to "%s"`, act.Name, act.FromList, act.ToList)
 }
 
+func (act MoveCardAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	toListID, err := ctx.ResolveID(act.ToList)
+	if err != nil {
+		return nil, err
+	}
+	return MoveCardAction{
+		CardID:      act.CardID,
+		Name:        act.Name,
+		FromList:    toListID,
+		ToList:      act.FromList,
+		Position:    act.OldPosition,
+		OldPosition: act.Position,
+		Pos:         act.OldPos,
+		OldPos:      act.Pos,
+	}, nil
+}
+
 type UpdateCardNameAction struct {
 	CardID  string
 	OldName string
 	NewName string
 }
 
-func (act UpdateCardNameAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (act UpdateCardNameAction) Apply(t trello.API, ctx *ActionContext) error {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return fmt.Errorf("card '%s' not found on board: %w", act.OldName, err)
+	}
+
 	params := &trello.UpdateCardParams{
-		ID:   act.CardID,
+		ID:   cardID,
 		Name: &act.NewName,
 	}
-	_, err := t.UpdateCard(params)
-	return fmt.Errorf(`Error failed to update card name: %w`, err)
+	if _, err := t.UpdateCard(params); err != nil {
+		return fmt.Errorf(`Error failed to update card name: %w`, err)
+	}
+	return nil
 }
 
 func (act UpdateCardNameAction) Description() string {
 	return fmt.Sprintf(`Card "%s" renamed to "%s"`, act.OldName, act.NewName)
 }
 
+func (act UpdateCardNameAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateCardNameAction{CardID: act.CardID, OldName: act.NewName, NewName: act.OldName}, nil
+}
+
 type UpdateCardPositionAction struct {
 	CardID      string
 	Name        string
 	OldPosition int
 	NewPosition int
+	OldPos      float64 // Trello positions, the markdown positions above are only for descriptions
+	Pos         float64
 }
 
-func (act UpdateCardPositionAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
-	posStr := fmt.Sprintf("%d.0", act.NewPosition)
+func (act UpdateCardPositionAction) Apply(t trello.API, ctx *ActionContext) error {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return fmt.Errorf("card '%s' not found on board: %w", act.Name, err)
+	}
+
+	posStr := formatPos(act.Pos)
 
 	params := &trello.UpdateCardParams{
-		ID:  act.CardID,
+		ID:  cardID,
 		Pos: &posStr,
 	}
-	_, err := t.UpdateCard(params)
-	return fmt.Errorf(`Error failed to update card position: %w`, err)
+	if _, err := t.UpdateCard(params); err != nil {
+		return fmt.Errorf(`Error failed to update card position: %w`, err)
+	}
+	return nil
 }
 
 func (act UpdateCardPositionAction) Description() string {
 	return fmt.Sprintf(`Card "%s" moved from position %d to %d`, act.Name, act.OldPosition, act.NewPosition)
 }
 
+func (act UpdateCardPositionAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateCardPositionAction{
+		CardID:      act.CardID,
+		Name:        act.Name,
+		OldPosition: act.NewPosition,
+		NewPosition: act.OldPosition,
+		OldPos:      act.Pos,
+		Pos:         act.OldPos,
+	}, nil
+}
+
 type UpdateCardIsCompletedAction struct {
 	CardID     string
 	Name       string
 	IsComplete bool
 }
 
-func (act UpdateCardIsCompletedAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (act UpdateCardIsCompletedAction) Apply(t trello.API, ctx *ActionContext) error {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return fmt.Errorf("card '%s' not found on board: %w", act.Name, err)
+	}
+
 	params := &trello.UpdateCardParams{
-		ID:          act.CardID,
+		ID:          cardID,
 		DueComplete: &act.IsComplete,
 	}
-	_, err := t.UpdateCard(params)
-	return fmt.Errorf(`Error failed to update card is completed field: %w`, err)
+	if _, err := t.UpdateCard(params); err != nil {
+		return fmt.Errorf(`Error failed to update card is completed field: %w`, err)
+	}
+	return nil
 }
 
 func (act UpdateCardIsCompletedAction) Description() string {
@@ -537,94 +828,72 @@ func (act UpdateCardIsCompletedAction) Description() string {
 	}
 }
 
+func (act UpdateCardIsCompletedAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateCardIsCompletedAction{CardID: act.CardID, Name: act.Name, IsComplete: !act.IsComplete}, nil
+}
+
 type AddCardLabelAction struct {
 	CardID    string
 	CardName  string
 	LabelName string
 }
 
-func (act AddCardLabelAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
-	board, err := t.GetBoard(ctx.BoardID)
+func (act AddCardLabelAction) Apply(t trello.API, ctx *ActionContext) error {
+	labelID, err := ctx.LabelID(t, act.LabelName)
 	if err != nil {
-		return fmt.Errorf("failed to get board: %w", err)
+		return err
 	}
 
-	var labelID string
-	for _, label := range board.Labels {
-		labelNameMarkdown := strings.ReplaceAll(label.Name, " ", "~")
-		if labelNameMarkdown == act.LabelName {
-			labelID = label.ID
-			break
-		}
-	}
-	if labelID == "" {
-		return fmt.Errorf("label '%s' not found on board", act.LabelName)
-	}
-
-	// Find the card by name (since ID might be sentinel)
-	lists, err := t.GetLists(ctx.BoardID)
+	cardID, err := ctx.ResolveID(act.CardID)
 	if err != nil {
-		return fmt.Errorf("failed to get lists: %w", err)
+		return fmt.Errorf("card '%s' not found on board: %w", act.CardName, err)
 	}
 
-	for _, list := range lists {
-		cards, err := t.GetCards(list.ID)
-		if err != nil {
-			continue // Skip this list if we can't get cards
-		}
-
-		for _, card := range cards {
-			if card.Name == act.CardName {
-				params := &trello.AddCardLabelParams{
-					ID:      card.ID,
-					Name:    &act.CardName,
-					LabelID: labelID,
-				}
-				return t.AddCardLabel(params)
-			}
-		}
+	params := &trello.AddCardLabelParams{
+		ID:      cardID,
+		Name:    &act.CardName,
+		LabelID: labelID,
 	}
-
-	return fmt.Errorf("card '%s' not found on board", act.CardName)
+	if err := t.AddCardLabel(params); err != nil {
+		return fmt.Errorf(`Error failed to add card label: %w`, err)
+	}
+	return nil
 }
 
 func (act AddCardLabelAction) Description() string {
 	return fmt.Sprintf(`Added label "%s" to card "%s"`, act.LabelName, act.CardName)
 }
 
+func (act AddCardLabelAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return nil, err
+	}
+	return DeleteCardLabelAction{CardID: cardID, CardName: act.CardName, LabelName: act.LabelName}, nil
+}
+
 type UpdateCardDueDate struct {
 	CardID string
 	Name   string
 	Due    string
-	Cfg    *config.Config
+	OldDue string
+	Cfg    *config.Config `json:"-"`
 }
 
-func (act UpdateCardDueDate) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
-	// Find the card by name, since ID might be sentinel ID
-	lists, err := t.GetLists(ctx.BoardID)
+func (act UpdateCardDueDate) Apply(t trello.API, ctx *ActionContext) error {
+	cardID, err := ctx.ResolveID(act.CardID)
 	if err != nil {
-		return fmt.Errorf("failed to get lists: %w", err)
+		return fmt.Errorf("card '%s' not found on board: %w", act.Name, err)
 	}
 
-	for _, list := range lists {
-		cards, err := t.GetCards(list.ID)
-		if err != nil {
-			continue // Skip this list if we can't get cards
-		}
-
-		for _, card := range cards {
-			if card.Name == act.Name {
-				params := &trello.UpdateCardParams{
-					ID:  card.ID,
-					Due: &act.Due,
-				}
-				_, err := t.UpdateCard(params)
-				return fmt.Errorf(`Error failed to update card: %w`, err)
-			}
-		}
+	params := &trello.UpdateCardParams{
+		ID:  cardID,
+		Due: &act.Due,
 	}
-
-	return fmt.Errorf("card '%s' not found on board", act.Name)
+	if _, err := t.UpdateCard(params); err != nil {
+		return fmt.Errorf(`Error failed to update card: %w`, err)
+	}
+	return nil
 }
 
 func (act UpdateCardDueDate) Description() string {
@@ -632,25 +901,51 @@ func (act UpdateCardDueDate) Description() string {
 	return fmt.Sprintf(`Card "%s" due date updated to "%s"`, act.Name, formatDate)
 }
 
+func (act UpdateCardDueDate) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return nil, err
+	}
+	if act.OldDue == "" {
+		return DeleteCardDueDate{CardID: cardID, Name: act.Name, OldDue: act.Due}, nil
+	}
+	return UpdateCardDueDate{CardID: cardID, Name: act.Name, Due: act.OldDue, OldDue: act.Due, Cfg: act.Cfg}, nil
+}
+
 type DeleteCardDueDate struct {
 	CardID string
 	Name   string
+	OldDue string
 }
 
-func (act DeleteCardDueDate) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (act DeleteCardDueDate) Apply(t trello.API, ctx *ActionContext) error {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return fmt.Errorf("card '%s' not found on board: %w", act.Name, err)
+	}
+
 	emptyString := ""
 	params := &trello.UpdateCardParams{
-		ID:  act.CardID,
+		ID:  cardID,
 		Due: &emptyString,
 	}
-	_, err := t.UpdateCard(params)
-	return fmt.Errorf(`Error failed to detele card due date: %w`, err)
+	if _, err := t.UpdateCard(params); err != nil {
+		return fmt.Errorf(`Error failed to detele card due date: %w`, err)
+	}
+	return nil
 }
 
 func (act DeleteCardDueDate) Description() string {
 	return fmt.Sprintf(`Card "%s" due date removed`, act.Name)
 }
 
+func (act DeleteCardDueDate) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	if act.OldDue == "" {
+		return nil, ErrNotReversible
+	}
+	return UpdateCardDueDate{CardID: act.CardID, Name: act.Name, Due: act.OldDue}, nil
+}
+
 type DeleteCardLabelAction struct {
 	CardID    string
 	CardName  string
@@ -658,30 +953,353 @@ type DeleteCardLabelAction struct {
 	LabelName string
 }
 
-func (act DeleteCardLabelAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
+func (act DeleteCardLabelAction) Apply(t trello.API, ctx *ActionContext) error {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return fmt.Errorf("card '%s' not found on board: %w", act.CardName, err)
+	}
+
+	labelID := act.LabelID
+	if labelID == "" {
+		foundID, err := ctx.LabelID(t, act.LabelName)
+		if err != nil {
+			return err
+		}
+		labelID = foundID
+	}
+
 	params := &trello.DeleteCardLabelParams{
-		ID:      act.CardID,
+		ID:      cardID,
 		Name:    &act.CardName,
-		LabelID: act.LabelID,
+		LabelID: labelID,
 	}
-	err := t.DeleteCardLabel(params)
-	return fmt.Errorf(`Error failed to delete card label: %w`, err)
+	if err := t.DeleteCardLabel(params); err != nil {
+		return fmt.Errorf(`Error failed to delete card label: %w`, err)
+	}
+	return nil
 }
 
 func (act DeleteCardLabelAction) Description() string {
 	return fmt.Sprintf(`Removed label "%s" from card "%s"`, act.LabelName, act.CardName)
 }
 
+func (act DeleteCardLabelAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return AddCardLabelAction{CardID: act.CardID, CardName: act.CardName, LabelName: act.LabelName}, nil
+}
+
 type DeleteCardAction struct {
-	Name   string
-	CardID string
+	Name        string
+	CardID      string
+	ListID      string
+	ListName    string
+	Position    int
+	Pos         float64 // Trello position, used to recreate the card in the same place
+	IsCompleted bool
+	Labels      []string
+	Due         string
 }
 
-func (act DeleteCardAction) Apply(t *trello.TrelloClient, ctx *ActionContext) error {
-	err := t.DeleteCard(act.CardID)
-	return fmt.Errorf(`Error deleting card: %w`, err)
+func (act DeleteCardAction) Apply(t trello.API, ctx *ActionContext) error {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return fmt.Errorf("card '%s' not found on board: %w", act.Name, err)
+	}
+
+	if err := t.DeleteCard(cardID); err != nil {
+		return fmt.Errorf(`Error deleting card: %w`, err)
+	}
+	return nil
 }
 
 func (act DeleteCardAction) Description() string {
-	return fmt.Sprintf(`List "%s" deleted`, act.Name)
+	return fmt.Sprintf(`Card "%s" deleted`, act.Name)
+}
+
+// The card is recreated from what the markdown showed, descriptions, comments and attachments are lost with it
+func (act DeleteCardAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	if act.ListID == "" {
+		return nil, ErrNotReversible
+	}
+	// The old ID lets the rollback resolve older inverses that still refer to the deleted card
+	return CreateCardAction{
+		CardID:      act.CardID,
+		ListID:      act.ListID,
+		ListName:    act.ListName,
+		Name:        act.Name,
+		Position:    act.Position,
+		Pos:         act.Pos,
+		IsCompleted: act.IsCompleted,
+		Labels:      act.Labels,
+		Due:         act.Due,
+	}, nil
+}
+
+// === CHECKLIST ACTIONS ===
+
+type CreateChecklistAction struct {
+	ChecklistID string // Sentinel ID from the markdown
+	CardID      string
+	CardName    string
+	Name        string
+	Position    int
+	CheckItems  []*ParsedCheckItem
+}
+
+func (act CreateChecklistAction) Apply(t trello.API, ctx *ActionContext) error {
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return fmt.Errorf("card '%s' not found on board: %w", act.CardName, err)
+	}
+
+	pos := "bottom"
+	params := &trello.CreateChecklistParams{
+		IdCard: cardID,
+		Name:   &act.Name,
+		Pos:    &pos,
+	}
+	checklist, err := t.CreateChecklist(params)
+	if err != nil {
+		return fmt.Errorf(`Error failed to create checklist: %w`, err)
+	}
+	ctx.RecordCreatedID(act.ChecklistID, checklist.ID)
+
+	for _, checkItem := range act.CheckItems {
+		itemParams := &trello.CreateCheckItemParams{
+			ID:      checklist.ID,
+			Name:    checkItem.Name,
+			Pos:     &pos,
+			Checked: &checkItem.IsComplete,
+		}
+		if _, err := t.CreateCheckItem(itemParams); err != nil {
+			return fmt.Errorf(`Error failed to create check item "%s": %w`, checkItem.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (act CreateChecklistAction) Description() string {
+	return fmt.Sprintf(`Created checklist "%s" with %d item(s) on card "%s"`, act.Name, len(act.CheckItems), act.CardName)
+}
+
+func (act CreateChecklistAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	checklistID, err := ctx.ResolveID(act.ChecklistID)
+	if err != nil {
+		return nil, err
+	}
+	cardID, err := ctx.ResolveID(act.CardID)
+	if err != nil {
+		return nil, err
+	}
+	return DeleteChecklistAction{
+		ChecklistID: checklistID,
+		Name:        act.Name,
+		CardID:      cardID,
+		CardName:    act.CardName,
+		Position:    act.Position,
+		CheckItems:  act.CheckItems,
+	}, nil
+}
+
+type UpdateChecklistNameAction struct {
+	ChecklistID string
+	OldName     string
+	NewName     string
+}
+
+func (act UpdateChecklistNameAction) Apply(t trello.API, ctx *ActionContext) error {
+	params := &trello.UpdateChecklistParams{
+		ID:   act.ChecklistID,
+		Name: &act.NewName,
+	}
+	if _, err := t.UpdateChecklist(params); err != nil {
+		return fmt.Errorf(`Error failed to update checklist name: %w`, err)
+	}
+	return nil
+}
+
+func (act UpdateChecklistNameAction) Description() string {
+	return fmt.Sprintf(`Checklist "%s" renamed to "%s"`, act.OldName, act.NewName)
+}
+
+func (act UpdateChecklistNameAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateChecklistNameAction{ChecklistID: act.ChecklistID, OldName: act.NewName, NewName: act.OldName}, nil
+}
+
+type DeleteChecklistAction struct {
+	ChecklistID string
+	Name        string
+	CardID      string
+	CardName    string
+	Position    int
+	CheckItems  []*ParsedCheckItem
+}
+
+func (act DeleteChecklistAction) Apply(t trello.API, ctx *ActionContext) error {
+	if err := t.DeleteChecklist(act.ChecklistID); err != nil {
+		return fmt.Errorf(`Error deleting checklist: %w`, err)
+	}
+	return nil
+}
+
+func (act DeleteChecklistAction) Description() string {
+	return fmt.Sprintf(`Checklist "%s" deleted from card "%s"`, act.Name, act.CardName)
+}
+
+func (act DeleteChecklistAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	if act.CardID == "" {
+		return nil, ErrNotReversible
+	}
+	return CreateChecklistAction{
+		CardID:     act.CardID,
+		CardName:   act.CardName,
+		Name:       act.Name,
+		Position:   act.Position,
+		CheckItems: act.CheckItems,
+	}, nil
+}
+
+type CreateCheckItemAction struct {
+	CheckItemID   string // Sentinel ID from the markdown
+	ChecklistID   string
+	ChecklistName string
+	Name          string
+	IsComplete    bool
+}
+
+func (act CreateCheckItemAction) Apply(t trello.API, ctx *ActionContext) error {
+	checklistID, err := ctx.ResolveID(act.ChecklistID)
+	if err != nil {
+		return fmt.Errorf(`checklist "%s" not found on board: %w`, act.ChecklistName, err)
+	}
+
+	pos := "bottom"
+	params := &trello.CreateCheckItemParams{
+		ID:      checklistID,
+		Name:    act.Name,
+		Pos:     &pos,
+		Checked: &act.IsComplete,
+	}
+	checkItem, err := t.CreateCheckItem(params)
+	if err != nil {
+		return fmt.Errorf(`Error failed to create check item: %w`, err)
+	}
+
+	ctx.RecordCreatedID(act.CheckItemID, checkItem.ID)
+	return nil
+}
+
+func (act CreateCheckItemAction) Description() string {
+	return fmt.Sprintf(`Added item "%s" to checklist "%s"`, act.Name, act.ChecklistName)
+}
+
+func (act CreateCheckItemAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	checklistID, err := ctx.ResolveID(act.ChecklistID)
+	if err != nil {
+		return nil, err
+	}
+	checkItemID, err := ctx.ResolveID(act.CheckItemID)
+	if err != nil {
+		return nil, err
+	}
+	if checkItemID == "" {
+		return nil, ErrNotReversible
+	}
+	return DeleteCheckItemAction{
+		ChecklistID:   checklistID,
+		ChecklistName: act.ChecklistName,
+		CheckItemID:   checkItemID,
+		Name:          act.Name,
+		IsComplete:    act.IsComplete,
+	}, nil
+}
+
+type UpdateCheckItemNameAction struct {
+	CardID      string
+	CheckItemID string
+	OldName     string
+	NewName     string
+}
+
+func (act UpdateCheckItemNameAction) Apply(t trello.API, ctx *ActionContext) error {
+	params := &trello.UpdateCheckItemParams{
+		ID:     act.CheckItemID,
+		CardID: act.CardID,
+		Name:   &act.NewName,
+	}
+	if _, err := t.UpdateCheckItem(params); err != nil {
+		return fmt.Errorf(`Error failed to update check item name: %w`, err)
+	}
+	return nil
+}
+
+func (act UpdateCheckItemNameAction) Description() string {
+	return fmt.Sprintf(`Check item "%s" renamed to "%s"`, act.OldName, act.NewName)
+}
+
+func (act UpdateCheckItemNameAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateCheckItemNameAction{CardID: act.CardID, CheckItemID: act.CheckItemID, OldName: act.NewName, NewName: act.OldName}, nil
+}
+
+type UpdateCheckItemStateAction struct {
+	CardID      string
+	CheckItemID string
+	Name        string
+	IsComplete  bool
+}
+
+func (act UpdateCheckItemStateAction) Apply(t trello.API, ctx *ActionContext) error {
+	state := "incomplete"
+	if act.IsComplete {
+		state = "complete"
+	}
+	params := &trello.UpdateCheckItemParams{
+		ID:     act.CheckItemID,
+		CardID: act.CardID,
+		State:  &state,
+	}
+	if _, err := t.UpdateCheckItem(params); err != nil {
+		return fmt.Errorf(`Error failed to update check item state: %w`, err)
+	}
+	return nil
+}
+
+func (act UpdateCheckItemStateAction) Description() string {
+	if act.IsComplete {
+		return fmt.Sprintf(`Check item "%s" checked`, act.Name)
+	} else {
+		return fmt.Sprintf(`Check item "%s" unchecked`, act.Name)
+	}
+}
+
+func (act UpdateCheckItemStateAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return UpdateCheckItemStateAction{CardID: act.CardID, CheckItemID: act.CheckItemID, Name: act.Name, IsComplete: !act.IsComplete}, nil
+}
+
+type DeleteCheckItemAction struct {
+	ChecklistID   string
+	ChecklistName string
+	CheckItemID   string
+	Name          string
+	IsComplete    bool
+}
+
+func (act DeleteCheckItemAction) Apply(t trello.API, ctx *ActionContext) error {
+	if err := t.DeleteCheckItem(act.ChecklistID, act.CheckItemID); err != nil {
+		return fmt.Errorf(`Error deleting check item: %w`, err)
+	}
+	return nil
+}
+
+func (act DeleteCheckItemAction) Description() string {
+	return fmt.Sprintf(`Check item "%s" deleted`, act.Name)
+}
+
+func (act DeleteCheckItemAction) Inverse(ctx *ActionContext) (TrelloAction, error) {
+	return CreateCheckItemAction{
+		ChecklistID:   act.ChecklistID,
+		ChecklistName: act.ChecklistName,
+		Name:          act.Name,
+		IsComplete:    act.IsComplete,
+	}, nil
 }
@@ -0,0 +1,139 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Boards, labels, lists and cards changed both in the editor and on trello while the editor was open are written
+// back with git style conflict markers, the user keeps one version and removes the markers
+
+const (
+	conflictStart     = "<<<<<<< local"
+	conflictSeparator = "======="
+	conflictEnd       = ">>>>>>> trello"
+)
+
+var ErrUnresolvedConflict = errors.New("unresolved conflict markers, keep one version of each conflicting item and remove the <<<<<<<, ======= and >>>>>>> lines")
+
+type ConflictKind int
+
+const (
+	ConflictBoard ConflictKind = iota
+	ConflictLabel
+	ConflictList
+	ConflictCard
+)
+
+// Conflict holds both versions of a board, label, list or card as markdown, a side is empty when the item was
+// deleted there
+type Conflict struct {
+	Kind        ConflictKind
+	ShortID     string
+	ListShortID string // List the local version of a card is in, used to place the conflict when trello no longer has the card
+	Local       string
+	Remote      string
+}
+
+func isConflictMarker(line string) bool {
+	return strings.HasPrefix(line, "<<<<<<<") || line == conflictSeparator || strings.HasPrefix(line, ">>>>>>>")
+}
+
+// ConflictBlock returns the lines showing the item with the given short ID, for a card that is the card line and
+// its indented checklist lines
+func ConflictBlock(content string, kind ConflictKind, shortID string) string {
+	lines := strings.Split(content, "\n")
+	start, end, found := findBlock(lines, kind, shortID)
+	if !found {
+		return ""
+	}
+	return strings.Join(lines[start:end], "\n")
+}
+
+func findBlock(lines []string, kind ConflictKind, shortID string) (int, int, bool) {
+	switch kind {
+	case ConflictCard:
+		return findCardBlock(lines, shortID)
+	case ConflictList:
+		line, found := findListLine(lines, shortID)
+		return line, line + 1, found
+	}
+
+	// The board heading and its label lines come before the first list
+	idTag := fmt.Sprintf("{%s}", shortID)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			break
+		}
+		isBoard := strings.HasPrefix(line, "# ")
+		isLabel := strings.HasPrefix(line, "@")
+		if ((kind == ConflictBoard && isBoard) || (kind == ConflictLabel && isLabel)) && strings.Contains(line, idTag) {
+			return i, i + 1, true
+		}
+	}
+	return 0, 0, false
+}
+
+func findCardBlock(lines []string, shortID string) (int, int, bool) {
+	idTag := fmt.Sprintf("{%s}", shortID)
+
+	for i, line := range lines {
+		if isIndented(line) || !cardRegex.MatchString(strings.TrimSpace(line)) || !strings.Contains(line, idTag) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && lines[end] != "" && isIndented(lines[end]) {
+			end++
+		}
+		return i, end, true
+	}
+
+	return 0, 0, false
+}
+
+// InsertConflictMarkers replaces each conflicting item in the content with both of its versions. A card trello
+// no longer has is placed at the top of the list the local version is in, or at the end when that list is gone
+func InsertConflictMarkers(content string, conflicts []Conflict) string {
+	lines := strings.Split(content, "\n")
+
+	for _, conflict := range conflicts {
+		conflictLines := []string{conflictStart}
+		if conflict.Local != "" {
+			conflictLines = append(conflictLines, conflict.Local)
+		}
+		conflictLines = append(conflictLines, conflictSeparator)
+		if conflict.Remote != "" {
+			conflictLines = append(conflictLines, conflict.Remote)
+		}
+		conflictLines = append(conflictLines, conflictEnd)
+
+		if start, end, found := findBlock(lines, conflict.Kind, conflict.ShortID); found {
+			lines = append(lines[:start], append(conflictLines, lines[end:]...)...)
+			continue
+		}
+
+		insertAt := len(lines)
+		if listLine, found := findListLine(lines, conflict.ListShortID); found {
+			insertAt = listLine + 1
+		}
+		lines = append(lines[:insertAt], append(conflictLines, lines[insertAt:]...)...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func findListLine(lines []string, shortID string) (int, bool) {
+	if shortID == "" {
+		return 0, false
+	}
+
+	idTag := fmt.Sprintf("{%s}", shortID)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") && strings.Contains(line, idTag) {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
 	"strconv"
 	"strings"
 
@@ -46,6 +47,7 @@ func generateDetailedActions(original, edited []markdown.DetailedTrelloAction) (
 		originalSection := originalMap[editedSection.ObjectName]
 
 		changedFields := make(map[string]any)
+		oldFields := make(map[string]any)
 		oldValues := make(map[string]string)
 		newValues := make(map[string]string)
 
@@ -57,33 +59,27 @@ func generateDetailedActions(original, edited []markdown.DetailedTrelloAction) (
 
 				apiFieldName, err := getAPIFieldName(editedSection.ObjectType, normalisedField)
 				if err != nil {
-					fmt.Printf("\nWarning: %v, skipping field %s\n", err, normalisedField)
+					fmt.Fprintf(os.Stderr, "\nWarning: %v, skipping field %s\n", err, normalisedField)
 					continue
 				}
 
 				apiValue, err := convertValueForAPI(apiFieldName, newValue)
 				if err != nil {
-					fmt.Printf("\nWarning: failed to convert value for %s: %v, skipping\n", apiFieldName, err)
+					fmt.Fprintf(os.Stderr, "\nWarning: failed to convert value for %s: %v, skipping\n", apiFieldName, err)
 					continue
 				}
 
 				changedFields[apiFieldName] = apiValue
+				if oldAPIValue := oldValueForAPI(apiFieldName, oldValue, apiValue); oldAPIValue != nil {
+					oldFields[apiFieldName] = oldAPIValue
+				}
 				oldValues[normalisedField] = oldValue
 				newValues[normalisedField] = newValue
-
-				if normalisedField == "Description" {
-					fmt.Printf("\n[%s] %s.%s updated to\n%s",
-						editedSection.ObjectType, editedSection.ObjectName, normalisedField, newValue)
-				} else {
-					fmt.Printf("\n[%s] %s.%s changed: '%s' -> '%s'",
-						editedSection.ObjectType, editedSection.ObjectName, normalisedField, oldValue, newValue)
-				}
-
 			}
 		}
 
 		if len(changedFields) > 0 {
-			action, err := markdown.CreateBulkUpdateAction(editedSection, changedFields, oldValues, newValues)
+			action, err := markdown.CreateBulkUpdateAction(editedSection, changedFields, oldFields, oldValues, newValues)
 			if err != nil {
 				return nil, err
 			}
@@ -94,6 +90,19 @@ func generateDetailedActions(original, edited []markdown.DetailedTrelloAction) (
 	return actions, nil
 }
 
+// oldValueForAPI converts the value a field had before the edit so the update can be undone. An empty text
+// field is restored by clearing it, anything else that cannot be converted cannot be restored
+func oldValueForAPI(apiField, oldValue string, newAPIValue any) any {
+	oldAPIValue, err := convertValueForAPI(apiField, oldValue)
+	if err != nil {
+		return nil
+	}
+	if _, isText := newAPIValue.(string); oldAPIValue == nil && isText {
+		return ""
+	}
+	return oldAPIValue
+}
+
 func normaliseFieldNames(fieldName string) string {
 	fieldName = strings.TrimSuffix(fieldName, ":")
 	fieldName = strings.TrimSpace(fieldName)
@@ -0,0 +1,223 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vinzmyko/mdello/markdown"
+	"github.com/vinzmyko/mdello/trello"
+)
+
+// MergeConflict is a board, label, list or card that was changed in the editor and on trello to different results
+type MergeConflict struct {
+	Kind markdown.ConflictKind
+	ID   string
+}
+
+// ThreeWayMerge compares the board when the editor opened, the edited board and the board as it is on trello now.
+// Items changed on both sides to different results are conflicts, they are reset to the original in the returned
+// board so diffing it against the original only applies the local changes that do not conflict. Boards, labels and
+// lists conflict when both sides rename them, cards when both sides change anything the markdown shows
+func ThreeWayMerge(original, edited, remote *markdown.ParsedBoard) (*markdown.ParsedBoard, []MergeConflict) {
+	var conflicts []MergeConflict
+
+	if changedOnBoth(original.Name, edited.Name, remote.Name) {
+		conflicts = append(conflicts, MergeConflict{Kind: markdown.ConflictBoard, ID: original.ID})
+	}
+
+	originalLabels := labelsByID(original)
+	editedLabels := labelsByID(edited)
+	remoteLabels := labelsByID(remote)
+	for _, labelID := range sortedKeys(originalLabels) {
+		editedLabel, inEdited := editedLabels[labelID]
+		remoteLabel, inRemote := remoteLabels[labelID]
+		if inEdited && inRemote && changedOnBoth(labelSignature(originalLabels[labelID]), labelSignature(editedLabel), labelSignature(remoteLabel)) {
+			conflicts = append(conflicts, MergeConflict{Kind: markdown.ConflictLabel, ID: labelID})
+		}
+	}
+
+	originalLists := listsByID(original)
+	editedLists := listsByID(edited)
+	remoteLists := listsByID(remote)
+	for _, listID := range sortedKeys(originalLists) {
+		editedList, inEdited := editedLists[listID]
+		remoteList, inRemote := remoteLists[listID]
+		if inEdited && inRemote && changedOnBoth(originalLists[listID].Name, editedList.Name, remoteList.Name) {
+			conflicts = append(conflicts, MergeConflict{Kind: markdown.ConflictList, ID: listID})
+		}
+	}
+
+	originalCards := cardsByID(original)
+	editedCards := cardsByID(edited)
+	remoteCards := cardsByID(remote)
+	conflictCards := make(map[string]bool)
+	for _, cardID := range sortedKeys(originalCards) {
+		originalCard := originalCards[cardID]
+		editedCard, inEdited := editedCards[cardID]
+		remoteCard, inRemote := remoteCards[cardID]
+
+		localChanged := !inEdited || cardSignature(editedCard) != cardSignature(originalCard)
+		remoteChanged := !inRemote || cardSignature(remoteCard) != cardSignature(originalCard)
+		if !localChanged || !remoteChanged {
+			continue
+		}
+
+		// Both sides made the same change, e.g. both deleted the card
+		if inEdited == inRemote && (!inEdited || cardSignature(editedCard) == cardSignature(remoteCard)) {
+			continue
+		}
+
+		conflictCards[cardID] = true
+		conflicts = append(conflicts, MergeConflict{Kind: markdown.ConflictCard, ID: cardID})
+	}
+
+	if len(conflicts) == 0 {
+		return edited, nil
+	}
+
+	// Put conflicting items back as they were so no action is created for them
+	merged := &markdown.ParsedBoard{
+		ID:           edited.ID,
+		Name:         edited.Name,
+		LabelLines:   edited.LabelLines,
+		DetailedEdit: edited.DetailedEdit,
+	}
+	conflictIDs := make(map[string]bool)
+	for _, conflict := range conflicts {
+		conflictIDs[conflict.ID] = true
+	}
+	if conflictIDs[original.ID] {
+		merged.Name = original.Name
+	}
+
+	// Cards refer to labels by name, so cards using a conflicting label go back to its original name too
+	labelNames := make(map[string]string)
+	for _, label := range edited.Labels {
+		if conflictIDs[label.ID] {
+			labelNames[label.Name] = originalLabels[label.ID].Name
+			label = originalLabels[label.ID]
+		}
+		merged.Labels = append(merged.Labels, label)
+	}
+
+	mergedLists := make(map[string]*markdown.ParsedList)
+	for _, editedList := range edited.Lists {
+		mergedList := *editedList
+		if conflictIDs[mergedList.ID] {
+			mergedList.Name = originalLists[mergedList.ID].Name
+		}
+		mergedList.Cards = nil
+		for _, card := range editedList.Cards {
+			if !conflictCards[card.ID] {
+				mergedList.Cards = append(mergedList.Cards, renameCardLabels(card, labelNames))
+			}
+		}
+		merged.Lists = append(merged.Lists, &mergedList)
+		mergedLists[mergedList.ID] = &mergedList
+	}
+
+	for _, conflict := range conflicts {
+		if conflict.Kind != markdown.ConflictCard {
+			continue
+		}
+		originalCard := originalCards[conflict.ID]
+		if mergedList, exists := mergedLists[originalCard.ListID]; exists {
+			mergedList.Cards = insertByTrelloPos(mergedList.Cards, originalCard, originalCards)
+		}
+	}
+
+	return merged, conflicts
+}
+
+func renameCardLabels(card *markdown.ParsedCard, labelNames map[string]string) *markdown.ParsedCard {
+	renamed := *card
+	renamed.Labels = make([]string, len(card.Labels))
+	for i, labelName := range card.Labels {
+		if originalName, found := labelNames[labelName]; found {
+			labelName = originalName
+		}
+		renamed.Labels[i] = labelName
+	}
+	return &renamed
+}
+
+// changedOnBoth reports whether both sides changed a value to different results
+func changedOnBoth(original, edited, remote string) bool {
+	return edited != original && remote != original && edited != remote
+}
+
+// insertByTrelloPos puts the card before the first card that was after it in the same list, so reinserting it
+// does not look like a move
+func insertByTrelloPos(cards []*markdown.ParsedCard, card *markdown.ParsedCard, originalCards map[string]*markdown.ParsedCard) []*markdown.ParsedCard {
+	for i, other := range cards {
+		originalOther, existed := originalCards[other.ID]
+		if existed && originalOther.ListID == card.ListID && originalOther.TrelloPos > card.TrelloPos {
+			return append(cards[:i], append([]*markdown.ParsedCard{card}, cards[i:]...)...)
+		}
+	}
+	return append(cards, card)
+}
+
+// Items without a trello ID yet are new and cannot conflict
+func labelsByID(board *markdown.ParsedBoard) map[string]*trello.Label {
+	labels := make(map[string]*trello.Label)
+	for _, label := range board.Labels {
+		if !markdown.IsSentinelID(label.ID) {
+			labels[label.ID] = label
+		}
+	}
+	return labels
+}
+
+func listsByID(board *markdown.ParsedBoard) map[string]*markdown.ParsedList {
+	lists := make(map[string]*markdown.ParsedList)
+	for _, list := range board.Lists {
+		if !markdown.IsSentinelID(list.ID) {
+			lists[list.ID] = list
+		}
+	}
+	return lists
+}
+
+func cardsByID(board *markdown.ParsedBoard) map[string]*markdown.ParsedCard {
+	cards := make(map[string]*markdown.ParsedCard)
+	for _, list := range board.Lists {
+		for _, card := range list.Cards {
+			if !markdown.IsSentinelID(card.ID) {
+				cards[card.ID] = card
+			}
+		}
+	}
+	return cards
+}
+
+func sortedKeys[T any](items map[string]T) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func labelSignature(label *trello.Label) string {
+	return label.Name + ":" + label.Colour
+}
+
+// cardSignature is everything the markdown shows about a card except its position, which shifts whenever
+// another card in the list is added or removed
+func cardSignature(card *markdown.ParsedCard) string {
+	labels := append([]string(nil), card.Labels...)
+	sort.Strings(labels)
+
+	var signature strings.Builder
+	fmt.Fprintf(&signature, "%s|%s|%s|%s|%s", card.ListID, card.Name, strings.ToLower(card.IsComplete), strings.Join(labels, ","), card.DueDate)
+	for _, checklist := range card.Checklists {
+		fmt.Fprintf(&signature, "|%s:%s", checklist.ID, checklist.Name)
+		for _, checkItem := range checklist.CheckItems {
+			fmt.Fprintf(&signature, ",%s:%s:%t", checkItem.ID, checkItem.Name, checkItem.IsComplete)
+		}
+	}
+	return signature.String()
+}
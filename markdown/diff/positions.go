@@ -0,0 +1,150 @@
+package diff
+
+import (
+	"sort"
+
+	"github.com/vinzmyko/mdello/markdown"
+)
+
+// Trello orders lists and cards by a float pos rather than an index. Moved and new items are placed at the midpoint
+// of their neighbours' positions, so only the items that actually moved need updating
+
+const (
+	positionGap    = 65536.0 // Spacing trello uses between positions
+	minPositionGap = 1.0     // Below this the container is renormalised instead of splitting the gap again
+)
+
+// placedItem is an item in its edited order. hasPos is set when the item is already in the container on trello
+type placedItem struct {
+	pos    float64
+	hasPos bool
+}
+
+// planPositions returns the new trello position of every item that has to move for the container to match the
+// edited order, keyed by the item's index. Items still in order relative to each other keep their position
+func planPositions(items []placedItem) map[int]float64 {
+	stable := stableItems(items)
+
+	positions := make(map[int]float64)
+	lower := 0.0
+	for i := 0; i < len(items); {
+		if stable[i] {
+			lower = items[i].pos
+			i++
+			continue
+		}
+
+		// Items between two stable neighbours are spread evenly across the gap between them
+		runStart := i
+		for i < len(items) && !stable[i] {
+			i++
+		}
+		runLength := i - runStart
+
+		if i == len(items) {
+			// Nothing stable below, place at the bottom
+			for j := 0; j < runLength; j++ {
+				positions[runStart+j] = lower + positionGap*float64(j+1)
+			}
+			continue
+		}
+
+		upper := items[i].pos
+		step := (upper - lower) / float64(runLength+1)
+		if step < minPositionGap {
+			return renormalisePositions(items)
+		}
+		for j := 0; j < runLength; j++ {
+			positions[runStart+j] = lower + step*float64(j+1)
+		}
+	}
+
+	return positions
+}
+
+// stableItems marks the longest run of existing items, not necessarily adjacent, whose positions are still in
+// increasing order. Those items stay put and everything else moves around them, so inserting or moving one card
+// only moves that card
+func stableItems(items []placedItem) []bool {
+	// tails[k] is the index of the smallest position ending an increasing subsequence of length k+1
+	var tails []int
+	previous := make([]int, len(items))
+
+	for i, item := range items {
+		previous[i] = -1
+		if !item.hasPos {
+			continue
+		}
+
+		length := sort.Search(len(tails), func(k int) bool {
+			return items[tails[k]].pos >= item.pos
+		})
+		if length > 0 {
+			previous[i] = tails[length-1]
+		}
+		if length == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[length] = i
+		}
+	}
+
+	stable := make([]bool, len(items))
+	if len(tails) == 0 {
+		return stable
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = previous[i] {
+		stable[i] = true
+	}
+	return stable
+}
+
+// renormalisePositions spaces every item evenly again, items already at their new position are left alone
+func renormalisePositions(items []placedItem) map[int]float64 {
+	positions := make(map[int]float64)
+	for i, item := range items {
+		pos := positionGap * float64(i+1)
+		if !item.hasPos || item.pos != pos {
+			positions[i] = pos
+		}
+	}
+	return positions
+}
+
+// planListPositions returns the new trello position of every list that is created or has to move, keyed by list ID
+func planListPositions(originalLists map[string]*markdown.ParsedList, editedLists []*markdown.ParsedList) map[string]float64 {
+	items := make([]placedItem, len(editedLists))
+	for i, editedList := range editedLists {
+		if originalList, exists := originalLists[editedList.ID]; exists {
+			items[i] = placedItem{pos: originalList.TrelloPos, hasPos: true}
+		}
+	}
+
+	listPositions := make(map[string]float64)
+	for i, pos := range planPositions(items) {
+		listPositions[editedLists[i].ID] = pos
+	}
+	return listPositions
+}
+
+// planCardPositions returns the new trello position of every card that is created, moved to another list or
+// reordered within its list, keyed by card ID
+func planCardPositions(originalCards map[string]*markdown.ParsedCard, editedLists []*markdown.ParsedList) map[string]float64 {
+	cardPositions := make(map[string]float64)
+
+	for _, editedList := range editedLists {
+		items := make([]placedItem, len(editedList.Cards))
+		for i, editedCard := range editedList.Cards {
+			// A card moved in from another list has no position in this one yet
+			if originalCard, exists := originalCards[editedCard.ID]; exists && originalCard.ListID == editedList.ID {
+				items[i] = placedItem{pos: originalCard.TrelloPos, hasPos: true}
+			}
+		}
+
+		for i, pos := range planPositions(items) {
+			cardPositions[editedList.Cards[i].ID] = pos
+		}
+	}
+
+	return cardPositions
+}
@@ -0,0 +1,148 @@
+package diff
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/vinzmyko/mdello/markdown"
+)
+
+func existing(positions ...float64) []placedItem {
+	items := make([]placedItem, len(positions))
+	for i, pos := range positions {
+		items[i] = placedItem{pos: pos, hasPos: true}
+	}
+	return items
+}
+
+func TestPlanPositions(t *testing.T) {
+	newItem := placedItem{}
+
+	tests := []struct {
+		name  string
+		items []placedItem
+		want  map[int]float64
+	}{
+		{
+			name:  "unchanged order moves nothing",
+			items: existing(65536, 131072, 196608),
+			want:  map[int]float64{},
+		},
+		{
+			name:  "new item at the bottom goes below the last item",
+			items: append(existing(65536, 131072), newItem),
+			want:  map[int]float64{2: 131072 + positionGap},
+		},
+		{
+			name:  "new item at the top goes between zero and the first item",
+			items: append([]placedItem{newItem}, existing(65536, 131072)...),
+			want:  map[int]float64{0: 32768},
+		},
+		{
+			name:  "new item between two items takes the midpoint",
+			items: []placedItem{{pos: 65536, hasPos: true}, newItem, {pos: 131072, hasPos: true}},
+			want:  map[int]float64{1: 98304},
+		},
+		{
+			name:  "several new items are spread evenly across the gap",
+			items: []placedItem{{pos: 100, hasPos: true}, newItem, newItem, newItem, {pos: 500, hasPos: true}},
+			want:  map[int]float64{1: 200, 2: 300, 3: 400},
+		},
+		{
+			name:  "every item is new in an empty container",
+			items: []placedItem{newItem, newItem},
+			want:  map[int]float64{0: positionGap, 1: 2 * positionGap},
+		},
+		{
+			name:  "a gap too small to split renormalises the container",
+			items: []placedItem{{pos: 1, hasPos: true}, newItem, {pos: 1.5, hasPos: true}},
+			want:  map[int]float64{0: positionGap, 1: 2 * positionGap, 2: 3 * positionGap},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := planPositions(test.items); !maps.Equal(got, test.want) {
+				t.Errorf("planPositions() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPlanCardPositionsPlacesCardsMovedFromAnotherList(t *testing.T) {
+	originalCards := map[string]*markdown.ParsedCard{
+		"a": {ID: "a", ListID: "todo", TrelloPos: 65536},
+		"b": {ID: "b", ListID: "todo", TrelloPos: 131072},
+		"c": {ID: "c", ListID: "done", TrelloPos: 65536},
+	}
+	editedLists := []*markdown.ParsedList{
+		{ID: "todo", Cards: []*markdown.ParsedCard{{ID: "a"}, {ID: "c"}, {ID: "b"}}},
+		{ID: "done"},
+	}
+
+	// c's position in done means nothing in todo, so it is placed between a and b
+	want := map[string]float64{"c": 98304}
+	if got := planCardPositions(originalCards, editedLists); !maps.Equal(got, want) {
+		t.Errorf("planCardPositions() = %v, want %v", got, want)
+	}
+}
+
+func TestStableItems(t *testing.T) {
+	newItem := placedItem{}
+
+	tests := []struct {
+		name  string
+		items []placedItem
+		want  []bool
+	}{
+		{
+			name:  "items in order are all stable",
+			items: existing(1, 2, 3),
+			want:  []bool{true, true, true},
+		},
+		{
+			name:  "moving the top item to the bottom only moves that item",
+			items: existing(2, 3, 4, 1),
+			want:  []bool{true, true, true, false},
+		},
+		{
+			name:  "moving the bottom item to the top only moves that item",
+			items: existing(4, 1, 2, 3),
+			want:  []bool{false, true, true, true},
+		},
+		{
+			name:  "swapping two items moves one of them",
+			items: existing(1, 3, 2, 4),
+			want:  []bool{true, false, true, true},
+		},
+		{
+			name:  "new items are never stable",
+			items: []placedItem{newItem, {pos: 1, hasPos: true}, newItem, {pos: 2, hasPos: true}},
+			want:  []bool{false, true, false, true},
+		},
+		{
+			name:  "a reversed container keeps one item",
+			items: existing(3, 2, 1),
+			want:  []bool{false, false, true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := stableItems(test.items); !slices.Equal(got, test.want) {
+				t.Errorf("stableItems() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPlanPositionsMovesOnlyTheMovedItem(t *testing.T) {
+	// The first card was dragged to the bottom of a long list
+	items := existing(1000, 2000, 3000, 4000, 5000, 6000, 500)
+
+	want := map[int]float64{6: 6000 + positionGap}
+	if got := planPositions(items); !maps.Equal(got, want) {
+		t.Errorf("planPositions() = %v, want %v", got, want)
+	}
+}
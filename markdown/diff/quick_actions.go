@@ -51,9 +51,14 @@ func QuickActionsDiff(originalBoard, editedBoard *markdown.ParsedBoard, cfg *con
 		}
 	}
 
-	for labelID, originalLabel := range originalLabelsMap {
-		if editedLabel, exists := editedLabelsMap[labelID]; exists {
+	// Renamed labels by their old name, cards still using a renamed label keep it through the rename
+	labelRenames := make(map[string]string)
+
+	// Maps are only used for lookups, iterating the board slices keeps the actions in the same order every run
+	for _, originalLabel := range originalBoard.Labels {
+		if editedLabel, exists := editedLabelsMap[originalLabel.ID]; exists {
 			if originalLabel.Name != editedLabel.Name {
+				labelRenames[originalLabel.Name] = editedLabel.Name
 				quickActions = append(quickActions, markdown.UpdateLabelName{
 					ID:      originalLabel.ID,
 					OldName: originalLabel.Name,
@@ -72,15 +77,17 @@ func QuickActionsDiff(originalBoard, editedBoard *markdown.ParsedBoard, cfg *con
 
 		} else {
 			quickActions = append(quickActions, markdown.DeleteLabelAction{
-				ID:   originalLabel.ID,
-				Name: originalLabel.Name,
+				ID:     originalLabel.ID,
+				Name:   originalLabel.Name,
+				Colour: originalLabel.Colour,
 			})
 		}
 	}
 
-	for labelID, editedLabel := range editedLabelsMap {
-		if _, exists := originalLabelsMap[labelID]; !exists {
+	for _, editedLabel := range editedBoard.Labels {
+		if _, exists := originalLabelsMap[editedLabel.ID]; !exists {
 			quickActions = append(quickActions, markdown.CreateLabelAction{
+				LabelID: editedLabel.ID,
 				BoardID: originalBoard.ID,
 				Name:    editedLabel.Name,
 				Colour:  editedLabel.Colour,
@@ -98,8 +105,11 @@ func QuickActionsDiff(originalBoard, editedBoard *markdown.ParsedBoard, cfg *con
 		editedListMap[list.ID] = list
 	}
 
-	for listID, originalList := range originalListsMap {
-		if editedList, exists := editedListMap[listID]; exists {
+	listPositions := planListPositions(originalListsMap, editedBoard.Lists)
+	cardPositions := planCardPositions(allOriginalCards, editedBoard.Lists)
+
+	for _, originalList := range originalBoard.Lists {
+		if editedList, exists := editedListMap[originalList.ID]; exists {
 			if originalList.Name != editedList.Name {
 				quickActions = append(quickActions, markdown.UpdateListNameAction{
 					ListID:  originalList.ID,
@@ -108,12 +118,14 @@ func QuickActionsDiff(originalBoard, editedBoard *markdown.ParsedBoard, cfg *con
 				})
 			}
 
-			if originalList.MarkdownIdx != editedList.MarkdownIdx {
+			if pos, moved := listPositions[originalList.ID]; moved {
 				quickActions = append(quickActions, markdown.UpdateListPositionAction{
 					ListID:      originalList.ID,
 					Name:        originalList.Name,
 					OldPosition: originalList.MarkdownIdx,
 					NewPosition: editedList.MarkdownIdx,
+					OldPos:      originalList.TrelloPos,
+					Pos:         pos,
 				})
 			}
 
@@ -131,18 +143,21 @@ func QuickActionsDiff(originalBoard, editedBoard *markdown.ParsedBoard, cfg *con
 				editedCardsMap[card.ID] = card
 			}
 
-			for cardID, originalCard := range originalCardsMap {
+			for _, originalCard := range originalList.Cards {
+				cardID := originalCard.ID
 				if editedCard, exists := editedCardsMap[cardID]; exists {
-					if originalCard.Position != editedCard.Position {
+					if pos, moved := cardPositions[cardID]; moved {
 						quickActions = append(quickActions, markdown.UpdateCardPositionAction{
 							CardID:      originalCard.ID,
 							Name:        originalCard.Name,
 							OldPosition: originalCard.Position,
 							NewPosition: editedCard.Position,
+							OldPos:      originalCard.TrelloPos,
+							Pos:         pos,
 						})
 					}
 
-					cardActions, err := checkCardProperties(originalCard, editedCard, cfg)
+					cardActions, err := checkCardProperties(originalCard, editedCard, labelRenames, cfg)
 					if err != nil {
 						return nil, fmt.Errorf("error checking card properties for card '%s': %w", originalCard.Name, err)
 					}
@@ -156,61 +171,50 @@ func QuickActionsDiff(originalBoard, editedBoard *markdown.ParsedBoard, cfg *con
 					// Checks to see if the cardID exists in another list, if it does exist it's moved to another list
 					if movedCard, movedToAnotherList := allEditedCards[cardID]; movedToAnotherList {
 						quickActions = append(quickActions, markdown.MoveCardAction{
-							CardID:   cardID,
-							Name:     movedCard.Name,
-							FromList: originalList.ID,
-							ToList:   movedCard.ListID,
-							Position: movedCard.Position,
+							CardID:      cardID,
+							Name:        movedCard.Name,
+							FromList:    originalList.ID,
+							ToList:      movedCard.ListID,
+							Position:    movedCard.Position,
+							OldPosition: originalCard.Position,
+							Pos:         cardPositions[cardID],
+							OldPos:      originalCard.TrelloPos,
 						})
 
-						cardActions, err := checkCardProperties(originalCard, movedCard, cfg)
+						cardActions, err := checkCardProperties(originalCard, movedCard, labelRenames, cfg)
 						if err != nil {
 							return nil, fmt.Errorf("error checking card properties for card '%s': %w", originalCard.Name, err)
 						}
 						quickActions = append(quickActions, cardActions...)
 
 					} else {
+						due, err := parseDueDate(originalCard.DueDate, cfg)
+						if err != nil {
+							return nil, err
+						}
 						quickActions = append(quickActions, markdown.DeleteCardAction{
-							CardID: originalCard.ID,
-							Name:   originalCard.Name,
+							CardID:      originalCard.ID,
+							Name:        originalCard.Name,
+							ListID:      originalList.ID,
+							ListName:    originalList.Name,
+							Position:    originalCard.Position,
+							Pos:         originalCard.TrelloPos,
+							IsCompleted: strings.ToLower(originalCard.IsComplete) == "x",
+							Labels:      originalCard.Labels,
+							Due:         due,
 						})
 					}
 				}
 			}
 
-			for cardID, editedCard := range editedCardsMap {
-				if _, exists := originalCardsMap[cardID]; !exists {
-					if _, existedAnywhere := allOriginalCards[cardID]; !existedAnywhere {
-						isComplete := strings.ToLower(editedCard.IsComplete) == "x"
-
-						// Composition when creating a new card
-						quickActions = append(quickActions, markdown.CreateCardAction{
-							ListName:    editedList.Name,
-							Name:        editedCard.Name,
-							Position:    editedCard.Position,
-							IsCompleted: isComplete,
-						})
-
-						for _, labelName := range editedCard.Labels {
-							quickActions = append(quickActions, markdown.AddCardLabelAction{
-								CardID:    cardID,
-								CardName:  editedCard.Name,
-								LabelName: labelName,
-							})
-						}
-
-						if editedCard.DueDate != "" {
-							rfcDateFormat, err := markdown.ParseMarkdownDate(editedCard.DueDate, cfg)
-							if err != nil {
-								return nil, fmt.Errorf("invalid due date format: %w", err)
-							}
-							quickActions = append(quickActions, markdown.UpdateCardDueDate{
-								CardID: cardID,
-								Name:   editedCard.Name,
-								Due:    rfcDateFormat,
-								Cfg:    cfg,
-							})
+			for _, editedCard := range editedList.Cards {
+				if _, exists := originalCardsMap[editedCard.ID]; !exists {
+					if _, existedAnywhere := allOriginalCards[editedCard.ID]; !existedAnywhere {
+						cardActions, err := createCardActions(editedList, editedCard, cardPositions[editedCard.ID], cfg)
+						if err != nil {
+							return nil, err
 						}
+						quickActions = append(quickActions, cardActions...)
 					}
 				}
 			}
@@ -224,44 +228,27 @@ func QuickActionsDiff(originalBoard, editedBoard *markdown.ParsedBoard, cfg *con
 		}
 	}
 
-	for listID, editedList := range editedListMap {
-		if _, exists := originalListsMap[listID]; !exists {
+	for _, editedList := range editedBoard.Lists {
+		if _, exists := originalListsMap[editedList.ID]; !exists {
 			quickActions = append(quickActions, markdown.CreateListAction{
+				ListID:   editedList.ID,
 				BoardID:  originalBoard.ID,
 				Name:     editedList.Name,
 				Position: editedList.MarkdownIdx,
+				Pos:      listPositions[editedList.ID],
 			})
 
 			for _, editedCard := range editedList.Cards {
-				isComplete := strings.ToLower(editedCard.IsComplete) == "x"
-
-				quickActions = append(quickActions, markdown.CreateCardAction{
-					ListName:    editedList.Name,
-					Name:        editedCard.Name,
-					Position:    editedCard.Position,
-					IsCompleted: isComplete,
-				})
-
-				for _, labelName := range editedCard.Labels {
-					quickActions = append(quickActions, markdown.AddCardLabelAction{
-						CardID:    editedCard.ID,
-						CardName:  editedCard.Name,
-						LabelName: labelName,
-					})
+				// Existing cards moved into the new list are handled by MoveCardAction
+				if _, existed := allOriginalCards[editedCard.ID]; existed {
+					continue
 				}
 
-				if editedCard.DueDate != "" {
-					rfcDateFormat, err := markdown.ParseMarkdownDate(editedCard.DueDate, cfg)
-					if err != nil {
-						return nil, fmt.Errorf("invalid due date format: %w", err)
-					}
-					quickActions = append(quickActions, markdown.UpdateCardDueDate{
-						CardID: editedCard.ID,
-						Name:   editedCard.Name,
-						Due:    rfcDateFormat,
-						Cfg:    cfg,
-					})
+				cardActions, err := createCardActions(editedList, editedCard, cardPositions[editedCard.ID], cfg)
+				if err != nil {
+					return nil, err
 				}
+				quickActions = append(quickActions, cardActions...)
 			}
 		}
 	}
@@ -274,7 +261,31 @@ func QuickActionsDiff(originalBoard, editedBoard *markdown.ParsedBoard, cfg *con
 	return &diffResult, nil
 }
 
-func checkCardProperties(originalCard, editedCard *markdown.ParsedCard, cfg *config.Config) ([]markdown.TrelloAction, error) {
+// A new card is created with its labels and due date in a single request, its checklists follow once it exists
+func createCardActions(editedList *markdown.ParsedList, editedCard *markdown.ParsedCard, pos float64, cfg *config.Config) ([]markdown.TrelloAction, error) {
+	rfcDateFormat, err := parseDueDate(editedCard.DueDate, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := []markdown.TrelloAction{
+		markdown.CreateCardAction{
+			CardID:      editedCard.ID,
+			ListID:      editedList.ID,
+			ListName:    editedList.Name,
+			Name:        editedCard.Name,
+			Position:    editedCard.Position,
+			Pos:         pos,
+			IsCompleted: strings.ToLower(editedCard.IsComplete) == "x",
+			Labels:      editedCard.Labels,
+			Due:         rfcDateFormat,
+		},
+	}
+
+	return append(actions, createChecklistActions(editedCard)...), nil
+}
+
+func checkCardProperties(originalCard, editedCard *markdown.ParsedCard, labelRenames map[string]string, cfg *config.Config) ([]markdown.TrelloAction, error) {
 	var actions []markdown.TrelloAction
 
 	if originalCard.Name != editedCard.Name {
@@ -294,9 +305,18 @@ func checkCardProperties(originalCard, editedCard *markdown.ParsedCard, cfg *con
 		})
 	}
 
+	// The original labels are compared by the name they have after any rename, a removed label keeps its old name
+	// as it is removed before the rename
+	renamedLabel := func(label string) string {
+		if newName, renamed := labelRenames[label]; renamed {
+			return newName
+		}
+		return label
+	}
+
 	originalCardLabelsMap := make(map[string]bool)
 	for _, label := range originalCard.Labels {
-		originalCardLabelsMap[label] = true
+		originalCardLabelsMap[renamedLabel(label)] = true
 	}
 
 	editedCardLabelsMap := make(map[string]bool)
@@ -306,7 +326,7 @@ func checkCardProperties(originalCard, editedCard *markdown.ParsedCard, cfg *con
 
 	// In original but not in edited
 	for _, label := range originalCard.Labels {
-		if !editedCardLabelsMap[label] {
+		if !editedCardLabelsMap[renamedLabel(label)] {
 			actions = append(actions, markdown.DeleteCardLabelAction{
 				CardID:    originalCard.ID,
 				CardName:  editedCard.Name,
@@ -327,28 +347,175 @@ func checkCardProperties(originalCard, editedCard *markdown.ParsedCard, cfg *con
 	}
 
 	if originalCard.DueDate != editedCard.DueDate {
+		oldDue, err := parseDueDate(originalCard.DueDate, cfg)
+		if err != nil {
+			return nil, err
+		}
+
 		if editedCard.DueDate == "" {
 			actions = append(actions, markdown.DeleteCardDueDate{
 				CardID: originalCard.ID,
 				Name:   editedCard.Name,
+				OldDue: oldDue,
 			})
 		} else {
-			rfcDateFormat, err := markdown.ParseMarkdownDate(editedCard.DueDate, cfg)
+			rfcDateFormat, err := parseDueDate(editedCard.DueDate, cfg)
 			if err != nil {
-				return nil, fmt.Errorf("invalid due date format: %w", err)
+				return nil, err
 			}
 			actions = append(actions, markdown.UpdateCardDueDate{
 				CardID: originalCard.ID,
 				Cfg:    cfg,
 				Name:   editedCard.Name,
 				Due:    rfcDateFormat,
+				OldDue: oldDue,
 			})
 		}
 	}
 
+	actions = append(actions, checkCardChecklists(originalCard, editedCard)...)
+
 	return actions, nil
 }
 
+func checkCardChecklists(originalCard, editedCard *markdown.ParsedCard) []markdown.TrelloAction {
+	var actions []markdown.TrelloAction
+
+	editedChecklistsMap := make(map[string]*markdown.ParsedChecklist)
+	for _, checklist := range editedCard.Checklists {
+		editedChecklistsMap[checklist.ID] = checklist
+	}
+
+	originalChecklistsMap := make(map[string]*markdown.ParsedChecklist)
+	for _, originalChecklist := range originalCard.Checklists {
+		originalChecklistsMap[originalChecklist.ID] = originalChecklist
+
+		editedChecklist, exists := editedChecklistsMap[originalChecklist.ID]
+		if !exists {
+			actions = append(actions, markdown.DeleteChecklistAction{
+				ChecklistID: originalChecklist.ID,
+				Name:        originalChecklist.Name,
+				CardID:      originalCard.ID,
+				CardName:    editedCard.Name,
+				Position:    originalChecklist.Position,
+				CheckItems:  originalChecklist.CheckItems,
+			})
+			continue
+		}
+
+		if originalChecklist.Name != editedChecklist.Name {
+			actions = append(actions, markdown.UpdateChecklistNameAction{
+				ChecklistID: originalChecklist.ID,
+				OldName:     originalChecklist.Name,
+				NewName:     editedChecklist.Name,
+			})
+		}
+
+		actions = append(actions, checkChecklistItems(originalCard.ID, originalChecklist, editedChecklist)...)
+	}
+
+	for _, editedChecklist := range editedCard.Checklists {
+		if _, exists := originalChecklistsMap[editedChecklist.ID]; !exists {
+			actions = append(actions, markdown.CreateChecklistAction{
+				ChecklistID: editedChecklist.ID,
+				CardID:      originalCard.ID,
+				CardName:    editedCard.Name,
+				Name:        editedChecklist.Name,
+				Position:    editedChecklist.Position,
+				CheckItems:  editedChecklist.CheckItems,
+			})
+		}
+	}
+
+	return actions
+}
+
+func checkChecklistItems(cardID string, originalChecklist, editedChecklist *markdown.ParsedChecklist) []markdown.TrelloAction {
+	var actions []markdown.TrelloAction
+
+	editedItemsMap := make(map[string]*markdown.ParsedCheckItem)
+	for _, checkItem := range editedChecklist.CheckItems {
+		editedItemsMap[checkItem.ID] = checkItem
+	}
+
+	originalItemsMap := make(map[string]*markdown.ParsedCheckItem)
+	for _, originalItem := range originalChecklist.CheckItems {
+		originalItemsMap[originalItem.ID] = originalItem
+
+		editedItem, exists := editedItemsMap[originalItem.ID]
+		if !exists {
+			actions = append(actions, markdown.DeleteCheckItemAction{
+				ChecklistID:   originalChecklist.ID,
+				ChecklistName: originalChecklist.Name,
+				CheckItemID:   originalItem.ID,
+				Name:          originalItem.Name,
+				IsComplete:    originalItem.IsComplete,
+			})
+			continue
+		}
+
+		if originalItem.Name != editedItem.Name {
+			actions = append(actions, markdown.UpdateCheckItemNameAction{
+				CardID:      cardID,
+				CheckItemID: originalItem.ID,
+				OldName:     originalItem.Name,
+				NewName:     editedItem.Name,
+			})
+		}
+
+		if originalItem.IsComplete != editedItem.IsComplete {
+			actions = append(actions, markdown.UpdateCheckItemStateAction{
+				CardID:      cardID,
+				CheckItemID: originalItem.ID,
+				Name:        editedItem.Name,
+				IsComplete:  editedItem.IsComplete,
+			})
+		}
+	}
+
+	for _, editedItem := range editedChecklist.CheckItems {
+		if _, exists := originalItemsMap[editedItem.ID]; !exists {
+			actions = append(actions, markdown.CreateCheckItemAction{
+				CheckItemID:   editedItem.ID,
+				ChecklistID:   originalChecklist.ID,
+				ChecklistName: editedChecklist.Name,
+				Name:          editedItem.Name,
+				IsComplete:    editedItem.IsComplete,
+			})
+		}
+	}
+
+	return actions
+}
+
+// Checklists on a newly created card are created along with all their check items
+func createChecklistActions(editedCard *markdown.ParsedCard) []markdown.TrelloAction {
+	var actions []markdown.TrelloAction
+	for _, checklist := range editedCard.Checklists {
+		actions = append(actions, markdown.CreateChecklistAction{
+			ChecklistID: checklist.ID,
+			CardID:      editedCard.ID,
+			CardName:    editedCard.Name,
+			Name:        checklist.Name,
+			Position:    checklist.Position,
+			CheckItems:  checklist.CheckItems,
+		})
+	}
+	return actions
+}
+
+// parseDueDate converts a markdown due date to the RFC3339 format trello expects, no due date stays empty
+func parseDueDate(dueDate string, cfg *config.Config) (string, error) {
+	if dueDate == "" {
+		return "", nil
+	}
+	rfcDateFormat, err := markdown.ParseMarkdownDate(dueDate, cfg)
+	if err != nil {
+		return "", fmt.Errorf("invalid due date format: %w", err)
+	}
+	return rfcDateFormat, nil
+}
+
 func createDetailedAction(objectType string, objectID string, objectName string) markdown.DetailedTrelloAction {
 	return markdown.DetailedTrelloAction{
 		ObjectType: objectType,
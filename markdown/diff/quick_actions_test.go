@@ -0,0 +1,54 @@
+package diff
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/vinzmyko/mdello/config"
+	"github.com/vinzmyko/mdello/markdown"
+	"github.com/vinzmyko/mdello/trello"
+)
+
+func boardWithLabel(labelName string, cardLabels ...[]string) *markdown.ParsedBoard {
+	list := &markdown.ParsedList{ID: "list", Name: "Todo", TrelloPos: positionGap}
+	for i, labels := range cardLabels {
+		list.Cards = append(list.Cards, &markdown.ParsedCard{
+			ID:        string(rune('a' + i)),
+			ListID:    "list",
+			Name:      string(rune('A' + i)),
+			Position:  i,
+			TrelloPos: float64(i+1) * positionGap,
+			Labels:    labels,
+		})
+	}
+	return &markdown.ParsedBoard{
+		ID:     "board",
+		Name:   "Board",
+		Labels: []*trello.Label{{ID: "label", Name: labelName, Colour: "red"}},
+		Lists:  []*markdown.ParsedList{list},
+	}
+}
+
+func TestQuickActionsDiffCarriesRenamedLabels(t *testing.T) {
+	original := boardWithLabel("bug", []string{"bug"}, []string{"bug"}, nil)
+	// A keeps the renamed label, B drops it and C picks it up
+	edited := boardWithLabel("defect", []string{"defect"}, nil, []string{"defect"})
+
+	result, err := QuickActionsDiff(original, edited, &config.Config{})
+	if err != nil {
+		t.Fatalf("QuickActionsDiff() failed: %v", err)
+	}
+
+	var got []string
+	for _, action := range result.QuickActions {
+		got = append(got, action.Description())
+	}
+	want := []string{
+		`Label "bug" renamed to "defect"`,
+		`Removed label "bug" from card "B"`,
+		`Added label "defect" to card "C"`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("QuickActionsDiff() actions:\n%q\nwant:\n%q", got, want)
+	}
+}
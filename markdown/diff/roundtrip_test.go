@@ -0,0 +1,244 @@
+package diff
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vinzmyko/mdello/config"
+	"github.com/vinzmyko/mdello/markdown"
+	"github.com/vinzmyko/mdello/trello"
+	"github.com/vinzmyko/mdello/trello/trellotest"
+)
+
+var shortIDRegex = regexp.MustCompile(` \{[^}]+\}`)
+
+var roundTripConfig = &config.Config{Profile: config.Profile{TimeZone: "UTC"}}
+
+// newRoundTripBoard seeds a fake trello with a small board:
+//
+//	# Project
+//	@bug:red
+//
+//	## To Do
+//	- [ ] Write tests @bug
+//	  - Steps
+//	    - [ ] Reproduce
+//	- [ ] Fix login
+//
+//	## Done
+//	- [x] Release
+func newRoundTripBoard(t *testing.T) (*trello.TrelloClient, string) {
+	t.Helper()
+	t.Setenv(config.EnvHome, t.TempDir())
+
+	server := trellotest.NewServer()
+	t.Cleanup(server.Close)
+
+	board := server.AddBoard("Project")
+	bug := server.AddLabel(board.ID, "bug", "red")
+	todo := server.AddList(board.ID, "To Do")
+	done := server.AddList(board.ID, "Done")
+	writeTests := server.AddCard(todo.ID, "Write tests")
+	server.AddCheckItem(server.AddChecklist(writeTests.ID, "Steps").ID, "Reproduce", false)
+	server.AddCard(todo.ID, "Fix login")
+	release := server.AddCard(done.ID, "Release")
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+	if err := client.AddCardLabel(&trello.AddCardLabelParams{ID: writeTests.ID, LabelID: bug.ID}); err != nil {
+		t.Fatalf("AddCardLabel() failed: %v", err)
+	}
+	complete := true
+	if _, err := client.UpdateCard(&trello.UpdateCardParams{ID: release.ID, DueComplete: &complete}); err != nil {
+		t.Fatalf("UpdateCard() failed: %v", err)
+	}
+	return client, board.ID
+}
+
+func pull(t *testing.T, client trello.API, boardID string) (string, *markdown.BoardSession) {
+	t.Helper()
+	snapshot, err := client.GetBoardSnapshot(boardID)
+	if err != nil {
+		t.Fatalf("GetBoardSnapshot() failed: %v", err)
+	}
+	content, session, err := markdown.ToMarkdown(roundTripConfig, snapshot)
+	if err != nil {
+		t.Fatalf("ToMarkdown() failed: %v", err)
+	}
+	return content, session
+}
+
+// roundTrip pulls the board, applies edit to the markdown the way 'mdello apply' would and returns the board
+// pulled again afterwards
+func roundTrip(t *testing.T, client trello.API, boardID string, edit func(content string) string) (edited, pulled string) {
+	t.Helper()
+	content, session := pull(t, client, boardID)
+	edited = edit(content)
+
+	originalBoard, err := markdown.FromMarkdown(strings.NewReader(content), session)
+	if err != nil {
+		t.Fatalf("parsing the pulled markdown failed: %v", err)
+	}
+	editedBoard, err := markdown.FromMarkdown(strings.NewReader(edited), session)
+	if err != nil {
+		t.Fatalf("parsing the edited markdown failed: %v", err)
+	}
+	if err := markdown.ValidateBoard(editedBoard, roundTripConfig); err != nil {
+		t.Fatalf("the edited markdown is not valid: %v", err)
+	}
+
+	result, err := QuickActionsDiff(originalBoard, editedBoard, roundTripConfig)
+	if err != nil {
+		t.Fatalf("QuickActionsDiff() failed: %v", err)
+	}
+	plan, err := markdown.PlanActions(result.QuickActions)
+	if err != nil {
+		t.Fatalf("PlanActions() failed: %v", err)
+	}
+
+	ctx := markdown.NewActionContext(boardID)
+	for _, stage := range plan {
+		for _, action := range stage {
+			if err := action.Apply(client, ctx); err != nil {
+				t.Fatalf("%s: %v", action.Description(), err)
+			}
+		}
+	}
+
+	pulled, _ = pull(t, client, boardID)
+	return edited, pulled
+}
+
+// assertRoundTrip checks the board pulled after applying reads the same as the edit. New items get their short
+// IDs when they are created, so the IDs are left out of the comparison
+func assertRoundTrip(t *testing.T, edited, pulled string) {
+	t.Helper()
+	edited = shortIDRegex.ReplaceAllString(edited, "")
+	pulled = shortIDRegex.ReplaceAllString(pulled, "")
+	if edited != pulled {
+		t.Errorf("the board pulled after applying:\n%s\ndoes not match the edit:\n%s", pulled, edited)
+	}
+}
+
+// line returns the line of content containing text
+func line(t *testing.T, content, text string) string {
+	t.Helper()
+	for _, candidate := range strings.Split(content, "\n") {
+		if strings.Contains(candidate, text) {
+			return candidate
+		}
+	}
+	t.Fatalf("no line contains %q in:\n%s", text, content)
+	return ""
+}
+
+func replaceLine(t *testing.T, content, text, replacement string) string {
+	t.Helper()
+	return strings.Replace(content, line(t, content, text)+"\n", replacement, 1)
+}
+
+func TestRoundTripPulledBoard(t *testing.T) {
+	client, boardID := newRoundTripBoard(t)
+
+	content, _ := pull(t, client, boardID)
+	want := `# Project
+@bug:red
+
+## To Do
+- [ ] Write tests @bug
+  - Steps
+    - [ ] Reproduce
+- [ ] Fix login
+
+## Done
+- [x] Release
+`
+	if got := shortIDRegex.ReplaceAllString(content, ""); got != want {
+		t.Errorf("pulled markdown:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRoundTripCreate(t *testing.T) {
+	client, boardID := newRoundTripBoard(t)
+
+	edited, pulled := roundTrip(t, client, boardID, func(content string) string {
+		content = replaceLine(t, content, "Fix login", line(t, content, "Fix login")+"\n"+
+			"- [ ] Add search @bug due:2025-07-25 09:30\n"+
+			"  - Plan\n"+
+			"    - [ ] Sketch\n"+
+			"    - [x] Ask around\n")
+		return content + "\n## Later\n- [ ] Dark mode\n"
+	})
+	assertRoundTrip(t, edited, pulled)
+}
+
+func TestRoundTripMove(t *testing.T) {
+	client, boardID := newRoundTripBoard(t)
+
+	edited, pulled := roundTrip(t, client, boardID, func(content string) string {
+		// Write tests moves to the top of Done with its checklist
+		writeTests := line(t, content, "Write tests") + "\n" + line(t, content, "Steps") + "\n" + line(t, content, "Reproduce") + "\n"
+		content = strings.Replace(content, writeTests, "", 1)
+		return replaceLine(t, content, "Release", writeTests+line(t, content, "Release")+"\n")
+	})
+	assertRoundTrip(t, edited, pulled)
+
+	edited, pulled = roundTrip(t, client, boardID, func(content string) string {
+		// Swap the lists
+		toDo := content[strings.Index(content, "## To Do"):strings.Index(content, "## Done")]
+		content = strings.Replace(content, toDo, "", 1)
+		return content + "\n" + strings.TrimSuffix(toDo, "\n")
+	})
+	assertRoundTrip(t, edited, pulled)
+}
+
+func TestRoundTripLabels(t *testing.T) {
+	client, boardID := newRoundTripBoard(t)
+
+	edited, pulled := roundTrip(t, client, boardID, func(content string) string {
+		content = strings.ReplaceAll(content, "@bug", "@defect")
+		content = strings.Replace(content, "@defect:red", "@defect:orange", 1)
+		content = replaceLine(t, content, "@defect:orange", line(t, content, "@defect:orange")+"\n@good~idea:green\n")
+		content = strings.Replace(content, "Fix login", "Fix login @defect @good~idea", 1)
+		return strings.Replace(content, "Release", "Release @good~idea", 1)
+	})
+	assertRoundTrip(t, edited, pulled)
+
+	edited, pulled = roundTrip(t, client, boardID, func(content string) string {
+		return strings.Replace(content, "Write tests @defect", "Write tests", 1)
+	})
+	assertRoundTrip(t, edited, pulled)
+}
+
+func TestRoundTripChecklists(t *testing.T) {
+	client, boardID := newRoundTripBoard(t)
+
+	edited, pulled := roundTrip(t, client, boardID, func(content string) string {
+		content = strings.Replace(content, "- Steps", "- Steps to reproduce", 1)
+		content = strings.Replace(content, "- [ ] Reproduce", "- [x] Reproduce", 1)
+		content = replaceLine(t, content, "Reproduce", line(t, content, "Reproduce")+"\n    - [ ] Write a failing test\n")
+		return replaceLine(t, content, "Fix login", line(t, content, "Fix login")+"\n  - Review\n    - [ ] Check the session timeout\n")
+	})
+	assertRoundTrip(t, edited, pulled)
+}
+
+func TestRoundTripDelete(t *testing.T) {
+	client, boardID := newRoundTripBoard(t)
+
+	edited, pulled := roundTrip(t, client, boardID, func(content string) string {
+		content = replaceLine(t, content, "@bug:red", "")
+		content = strings.Replace(content, " @bug", "", 1)
+		content = replaceLine(t, content, "Reproduce", "")
+		return replaceLine(t, content, "Release", "")
+	})
+	assertRoundTrip(t, edited, pulled)
+
+	edited, pulled = roundTrip(t, client, boardID, func(content string) string {
+		content = replaceLine(t, content, "Steps", "")
+		return replaceLine(t, content, "Fix login", "")
+	})
+	assertRoundTrip(t, edited, pulled)
+}
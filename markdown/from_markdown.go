@@ -20,8 +20,28 @@ var (
 	cardLabelRegex = regexp.MustCompile(`@([^:\s{]+)`)
 	cardDueRegex   = regexp.MustCompile(`due:([\d-]+(?:\s+[\d:]+)?)`)
 	cardIDRegex    = regexp.MustCompile(`\{([^}]+)\}`)
+
+	checklistRegex = regexp.MustCompile(`^- (.+?)(?:\s*\{([^}]+)\})?$`)
+	checkItemRegex = regexp.MustCompile(`^- \[([ xX]?)\] (.+?)(?:\s*\{([^}]+)\})?$`)
 )
 
+// ParseError is a line of the markdown that could not be parsed
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Name given to checklists when check items are written under a card without a checklist heading
+const defaultChecklistName = "Checklist"
+
 func FromMarkdown(r io.Reader, boardSession *BoardSession) (*ParsedBoard, error) {
 	scanner := bufio.NewScanner(r)
 	lineNum := 0
@@ -32,20 +52,39 @@ func FromMarkdown(r io.Reader, boardSession *BoardSession) (*ParsedBoard, error)
 	}
 
 	var currentList *ParsedList
+	var currentCard *ParsedCard
+	var currentChecklist *ParsedChecklist
 	listPosition := 0
 	inLabelSection := false
 
 	for scanner.Scan() {
 		lineNum++
-		line := strings.TrimSpace(scanner.Text())
+		rawLine := scanner.Text()
+		line := strings.TrimSpace(rawLine)
 		if line == "" {
 			continue
 		}
+		if isConflictMarker(line) {
+			return nil, &ParseError{Line: lineNum, Err: ErrUnresolvedConflict}
+		}
+		if isComment(line) {
+			continue
+		}
+
+		// Indented lines under a card belong to that card's checklists
+		if currentCard != nil && isIndented(rawLine) {
+			checklist, err := parseChecklistLine(line, currentCard, currentChecklist, boardSession)
+			if err != nil {
+				return nil, &ParseError{Line: lineNum, Err: err}
+			}
+			currentChecklist = checklist
+			continue
+		}
 
 		if name, id, detailedEdit := parseHeadingFields(boardRegex, line); name != "" {
 			resolvedBoardID, err := boardSession.ResolveShortID(id)
 			if err != nil {
-				return nil, fmt.Errorf("Failed to convert board shortID back to trelloID: %w", err)
+				return nil, &ParseError{Line: lineNum, Err: fmt.Errorf("Failed to convert board shortID back to trelloID: %w", err)}
 			}
 			parsedBoard.Name = name
 			parsedBoard.ID = resolvedBoardID
@@ -57,9 +96,10 @@ func FromMarkdown(r io.Reader, boardSession *BoardSession) (*ParsedBoard, error)
 		// Before any lists are defined
 		if inLabelSection {
 			if label, err := extractBoardLabels(line, boardSession); err != nil {
-				return nil, fmt.Errorf("error parsing board label: %w", err)
+				return nil, &ParseError{Line: lineNum, Err: fmt.Errorf("error parsing board label: %w", err)}
 			} else if label != nil {
 				parsedBoard.Labels = append(parsedBoard.Labels, label)
+				parsedBoard.LabelLines = append(parsedBoard.LabelLines, lineNum)
 				continue
 			}
 		}
@@ -68,17 +108,20 @@ func FromMarkdown(r io.Reader, boardSession *BoardSession) (*ParsedBoard, error)
 			inLabelSection = false
 			resolvedListID, err := boardSession.ResolveShortID(id)
 			if err != nil {
-				return nil, fmt.Errorf("Failed to convert board shortID back to trelloID: %w", err)
+				return nil, &ParseError{Line: lineNum, Err: fmt.Errorf("Failed to convert list shortID back to trelloID: %w", err)}
 			}
 			newList := &ParsedList{
 				ID:           resolvedListID,
 				Name:         name,
 				MarkdownIdx:  listPosition,
+				Line:         lineNum,
 				Cards:        make([]*ParsedCard, 0),
 				DetailedEdit: detailedEdit,
 			}
 			parsedBoard.Lists = append(parsedBoard.Lists, newList)
 			currentList = newList
+			currentCard = nil
+			currentChecklist = nil
 			listPosition++
 
 			continue
@@ -88,17 +131,20 @@ func FromMarkdown(r io.Reader, boardSession *BoardSession) (*ParsedBoard, error)
 		if currentList != nil {
 			card, err := parseCardLine(line, currentList.ID, boardSession)
 			if err != nil {
-				return nil, err
+				return nil, &ParseError{Line: lineNum, Err: err}
 			}
 			if card != nil {
 				card.Position = len(currentList.Cards)
+				card.Line = lineNum
 				currentList.Cards = append(currentList.Cards, card)
+				currentCard = card
+				currentChecklist = nil
 				continue
 			}
 		}
 
 		if inLabelSection {
-			return nil, fmt.Errorf("line %d: unexpected content in label section: %s", lineNum, line)
+			return nil, &ParseError{Line: lineNum, Err: fmt.Errorf("unexpected content in label section: %s", line)}
 		}
 	}
 
@@ -106,9 +152,73 @@ func FromMarkdown(r io.Reader, boardSession *BoardSession) (*ParsedBoard, error)
 		return nil, fmt.Errorf("error reading markdown source: %w", err)
 	}
 
+	reconcileMissingIDs(parsedBoard, boardSession)
+
 	return parsedBoard, nil
 }
 
+// reconcileMissingIDs matches items written without an ID to an existing item with the same name, so an
+// accidentally deleted ID doesn't turn an existing item into a duplicate. Cards are matched across the whole
+// board since they may have been moved, checklists and check items only within their parent
+func reconcileMissingIDs(parsedBoard *ParsedBoard, boardSession *BoardSession) {
+	usedIDs := make(map[string]bool)
+	for _, label := range parsedBoard.Labels {
+		usedIDs[label.ID] = true
+	}
+	for _, list := range parsedBoard.Lists {
+		usedIDs[list.ID] = true
+		for _, card := range list.Cards {
+			usedIDs[card.ID] = true
+			for _, checklist := range card.Checklists {
+				usedIDs[checklist.ID] = true
+				for _, checkItem := range checklist.CheckItems {
+					usedIDs[checkItem.ID] = true
+				}
+			}
+		}
+	}
+
+	match := func(id string, kind itemKind, name, parentID string) string {
+		if !IsSentinelID(id) {
+			return id
+		}
+		if matchedID, found := boardSession.matchExistingItem(kind, name, parentID, usedIDs); found {
+			usedIDs[matchedID] = true
+			return matchedID
+		}
+		return id
+	}
+
+	for _, label := range parsedBoard.Labels {
+		label.ID = match(label.ID, kindLabel, label.Name, "")
+	}
+
+	for _, list := range parsedBoard.Lists {
+		list.ID = match(list.ID, kindList, list.Name, "")
+		list.TrelloPos = boardSession.trelloPos(list.ID)
+
+		for _, card := range list.Cards {
+			card.ListID = list.ID
+			card.ID = match(card.ID, kindCard, card.Name, "")
+			card.TrelloPos = boardSession.trelloPos(card.ID)
+
+			for _, checklist := range card.Checklists {
+				checklist.CardID = card.ID
+				if !IsSentinelID(card.ID) {
+					checklist.ID = match(checklist.ID, kindChecklist, checklist.Name, card.ID)
+				}
+
+				for _, checkItem := range checklist.CheckItems {
+					checkItem.ChecklistID = checklist.ID
+					if !IsSentinelID(checklist.ID) {
+						checkItem.ID = match(checkItem.ID, kindCheckItem, checkItem.Name, checklist.ID)
+					}
+				}
+			}
+		}
+	}
+}
+
 func parseCardLine(line string, listID string, boardSession *BoardSession) (*ParsedCard, error) {
 	matches := cardRegex.FindStringSubmatch(line)
 	if len(matches) < 3 {
@@ -137,8 +247,12 @@ func parseCardLine(line string, listID string, boardSession *BoardSession) (*Par
 	tempText = cardDueRegex.ReplaceAllString(tempText, "")
 	tempText = cardIDRegex.ReplaceAllString(tempText, "")
 
-	if invalidLabelPattern := regexp.MustCompile(`@\w+\s+\w`).FindString(tempText); invalidLabelPattern != "" {
-		return nil, fmt.Errorf("invalid label format: labels cannot contain spaces. Use ~ for spaces (e.g., @front~end for 'front end')")
+	if invalidLabelPattern := regexp.MustCompile(`@\w+\s+\w+`).FindString(tempText); invalidLabelPattern != "" {
+		// A due date without its colon reads like a label followed by more text
+		if strings.HasSuffix(invalidLabelPattern, " due") {
+			return nil, fmt.Errorf("invalid due date in '%s': due dates need a colon, e.g. due:2025-01-31", invalidLabelPattern)
+		}
+		return nil, fmt.Errorf("invalid label format '%s': labels cannot contain spaces. Use ~ for spaces (e.g., @front~end for 'front end')", invalidLabelPattern)
 	}
 
 	labelMatches := cardLabelRegex.FindAllStringSubmatch(cardText, -1)
@@ -171,6 +285,69 @@ func parseCardLine(line string, listID string, boardSession *BoardSession) (*Par
 	return card, nil
 }
 
+// HTML comments are ignored so notes, like the problems mdello found in an edit, can be left in the markdown
+func isComment(line string) bool {
+	return strings.HasPrefix(line, "<!--") && strings.HasSuffix(line, "-->")
+}
+
+func isIndented(rawLine string) bool {
+	return strings.HasPrefix(rawLine, " ") || strings.HasPrefix(rawLine, "\t")
+}
+
+// parseChecklistLine handles a line nested under a card. A line with a checkbox is a check item of the current
+// checklist, any other list item starts a new checklist. Returns the checklist that following check items belong to
+func parseChecklistLine(line string, card *ParsedCard, currentChecklist *ParsedChecklist, boardSession *BoardSession) (*ParsedChecklist, error) {
+	if matches := checkItemRegex.FindStringSubmatch(line); len(matches) > 2 {
+		if currentChecklist == nil {
+			sentinelID, err := boardSession.ResolveShortID("")
+			if err != nil {
+				return nil, err
+			}
+			currentChecklist = &ParsedChecklist{
+				ID:       sentinelID,
+				CardID:   card.ID,
+				Name:     defaultChecklistName,
+				Position: len(card.Checklists),
+			}
+			card.Checklists = append(card.Checklists, currentChecklist)
+		}
+
+		resolvedCheckItemID, err := boardSession.ResolveShortID(matches[3])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to convert check item shortID back to trelloID: %w", err)
+		}
+
+		currentChecklist.CheckItems = append(currentChecklist.CheckItems, &ParsedCheckItem{
+			ID:          resolvedCheckItemID,
+			ChecklistID: currentChecklist.ID,
+			Name:        strings.TrimSpace(matches[2]),
+			Position:    len(currentChecklist.CheckItems),
+			IsComplete:  strings.ToLower(matches[1]) == "x",
+		})
+		return currentChecklist, nil
+	}
+
+	matches := checklistRegex.FindStringSubmatch(line)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("%s: checklist lines under a card must start with '- ', check items with '- [ ]'", line)
+	}
+
+	resolvedChecklistID, err := boardSession.ResolveShortID(matches[2])
+	if err != nil {
+		return nil, fmt.Errorf("Failed to convert checklist shortID back to trelloID: %w", err)
+	}
+
+	checklist := &ParsedChecklist{
+		ID:       resolvedChecklistID,
+		CardID:   card.ID,
+		Name:     strings.TrimSpace(matches[1]),
+		Position: len(card.Checklists),
+	}
+	card.Checklists = append(card.Checklists, checklist)
+
+	return checklist, nil
+}
+
 func parseHeadingFields(re *regexp.Regexp, line string) (name, id string, detailedEdit bool) {
 	matches := re.FindStringSubmatch(line)
 	if len(matches) < 2 {
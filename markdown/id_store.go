@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/vinzmyko/mdello/config"
+)
+
+// Short IDs are persisted per board so a short ID always refers to the same trello object between sessions
+
+func idMappingsFile(boardID string) (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	idsDir := filepath.Join(configDir, "ids")
+	if err := os.MkdirAll(idsDir, 0700); err != nil {
+		return "", fmt.Errorf("could not create id mappings directory: %w", err)
+	}
+
+	return filepath.Join(idsDir, fmt.Sprintf("%s.json", boardID)), nil
+}
+
+func loadIDMappings(boardID string) (map[string]string, error) {
+	mappingsFile, err := idMappingsFile(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(mappingsFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read id mappings: %w", err)
+	}
+
+	var shortToFull map[string]string
+	if err := json.Unmarshal(data, &shortToFull); err != nil {
+		return nil, fmt.Errorf("could not unmarshal id mappings: %w", err)
+	}
+
+	return shortToFull, nil
+}
+
+func saveIDMappings(boardID string, shortToFull map[string]string) error {
+	mappingsFile, err := idMappingsFile(boardID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(shortToFull, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal id mappings: %w", err)
+	}
+
+	if err := os.WriteFile(mappingsFile, data, 0600); err != nil {
+		return fmt.Errorf("could not save id mappings: %w", err)
+	}
+
+	return nil
+}
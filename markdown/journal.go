@@ -0,0 +1,316 @@
+package markdown
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vinzmyko/mdello/config"
+	"github.com/vinzmyko/mdello/trello"
+)
+
+// Every run that changes a board writes a journal to ~/.mdello/journal/ as its actions are applied. Each entry
+// stores the action and its inverse, so a run that fails partway can be rolled back
+
+type JournalStatus string
+
+const (
+	JournalApplied    JournalStatus = "applied"
+	JournalFailed     JournalStatus = "failed"
+	JournalRolledBack JournalStatus = "rolled_back"
+	JournalSkipped    JournalStatus = "skipped" // Could not be undone, or was not applied as an action it needs failed
+)
+
+type Journal struct {
+	BoardID   string         `json:"boardId"`
+	StartedAt time.Time      `json:"startedAt"`
+	Entries   []JournalEntry `json:"entries"`
+	path      string
+	saveErr   error      // Set while the file on disk is missing entries, see SaveErr
+	mu        sync.Mutex // Actions applied in parallel record their entries one at a time
+}
+
+type JournalEntry struct {
+	Description string         `json:"description"`
+	Status      JournalStatus  `json:"status"`
+	Error       string         `json:"error,omitempty"`
+	Action      *journalAction `json:"action"`
+	Inverse     *journalAction `json:"inverse,omitempty"` // nil when the action cannot be undone
+}
+
+// journalAction is a TrelloAction stored with its type name so it can be read back
+type journalAction struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+var journalActionTypes = registerActionTypes(
+	DetailedUpdateBoardAction{}, UpdateBoardNameAction{},
+	CreateLabelAction{}, UpdateLabelName{}, UpdateLabelColour{}, DeleteLabelAction{},
+	DetailedUpdateListAction{}, CreateListAction{}, UpdateListNameAction{}, UpdateListPositionAction{}, ArchiveListAction{},
+	DetailedUpdateCardAction{}, CreateCardAction{}, MoveCardAction{}, UpdateCardNameAction{}, UpdateCardPositionAction{},
+	UpdateCardIsCompletedAction{}, AddCardLabelAction{}, UpdateCardDueDate{}, DeleteCardDueDate{}, DeleteCardLabelAction{},
+	DeleteCardAction{},
+	CreateChecklistAction{}, UpdateChecklistNameAction{}, DeleteChecklistAction{},
+	CreateCheckItemAction{}, UpdateCheckItemNameAction{}, UpdateCheckItemStateAction{}, DeleteCheckItemAction{},
+)
+
+func registerActionTypes(actions ...TrelloAction) map[string]reflect.Type {
+	actionTypes := make(map[string]reflect.Type, len(actions))
+	for _, action := range actions {
+		actionType := reflect.TypeOf(action)
+		actionTypes[actionType.Name()] = actionType
+	}
+	return actionTypes
+}
+
+func NewJournal(boardID string) *Journal {
+	return &Journal{
+		BoardID:   boardID,
+		StartedAt: time.Now(),
+	}
+}
+
+// Path is empty until the first action has been recorded
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Apply applies the action and records it in the journal along with its inverse. The error is only the action's,
+// a journal that could not be written is reported by SaveErr so an applied action is never retried
+func (j *Journal) Apply(t trello.API, ctx *ActionContext, action TrelloAction) error {
+	encodedAction, err := encodeAction(action)
+	if err != nil {
+		return err
+	}
+
+	entry := JournalEntry{
+		Description: action.Description(),
+		Status:      JournalApplied,
+		Action:      encodedAction,
+	}
+
+	applyErr := action.Apply(t, ctx)
+	if applyErr != nil {
+		entry.Status = JournalFailed
+		entry.Error = applyErr.Error()
+	} else if inverse, err := action.Inverse(ctx); err == nil {
+		// An inverse that cannot be encoded leaves the entry without one, like an action that cannot be undone
+		entry.Inverse, _ = encodeAction(inverse)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Entries = append(j.Entries, entry)
+	j.saveErr = j.save() // Every save writes all entries, so a later one that succeeds catches up
+	return applyErr
+}
+
+// SaveErr is the error from the last write of the journal, non-nil means undo would miss some applied changes
+func (j *Journal) SaveErr() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.saveErr
+}
+
+var ErrNoJournal = errors.New("no changes to undo")
+
+// LoadLatestJournal returns the newest journal for the board that still has applied changes. Journals that were
+// rolled back are skipped, so each undo steps one session further back
+func LoadLatestJournal(boardID string) (*Journal, error) {
+	journalDir, err := journalDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dirEntries, err := os.ReadDir(journalDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read journal directory: %w", err)
+	}
+
+	var journalFiles []string
+	for _, dirEntry := range dirEntries {
+		if strings.HasPrefix(dirEntry.Name(), boardID+"-") && strings.HasSuffix(dirEntry.Name(), ".json") {
+			journalFiles = append(journalFiles, dirEntry.Name())
+		}
+	}
+	// File names end in their start time so the newest journal sorts last
+	sort.Sort(sort.Reverse(sort.StringSlice(journalFiles)))
+
+	for _, journalFile := range journalFiles {
+		journal, err := loadJournal(filepath.Join(journalDir, journalFile))
+		if err != nil {
+			return nil, err
+		}
+		if journal.hasApplied() {
+			return journal, nil
+		}
+	}
+
+	return nil, ErrNoJournal
+}
+
+func loadJournal(path string) (*Journal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read journal: %w", err)
+	}
+
+	journal := &Journal{}
+	if err := json.Unmarshal(data, journal); err != nil {
+		return nil, fmt.Errorf("could not parse journal %s: %w", filepath.Base(path), err)
+	}
+	journal.path = path
+
+	return journal, nil
+}
+
+func (j *Journal) hasApplied() bool {
+	for _, entry := range j.Entries {
+		if entry.Status == JournalApplied {
+			return true
+		}
+	}
+	return false
+}
+
+// Inverses returns the actions Rollback would apply, newest first, and the descriptions of applied
+// changes that cannot be undone
+func (j *Journal) Inverses() ([]TrelloAction, []string, error) {
+	var inverses []TrelloAction
+	var irreversible []string
+
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		entry := j.Entries[i]
+		if entry.Status != JournalApplied {
+			continue
+		}
+		if entry.Inverse == nil {
+			irreversible = append(irreversible, entry.Description)
+			continue
+		}
+
+		inverse, err := decodeAction(entry.Inverse)
+		if err != nil {
+			return nil, nil, err
+		}
+		inverses = append(inverses, inverse)
+	}
+
+	return inverses, irreversible, nil
+}
+
+// Rollback undoes every applied entry, newest first. Entries that cannot be undone are skipped, entries that
+// fail to undo are reported together once everything else has been tried. A deleted card comes back with a new
+// ID, ctx maps its old ID to the new one for the older entries that still refer to it
+func (j *Journal) Rollback(t trello.API, out io.Writer) error {
+	ctx := NewActionContext(j.BoardID)
+	var errs []error
+
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		entry := &j.Entries[i]
+		if entry.Status != JournalApplied {
+			continue
+		}
+		if entry.Inverse == nil {
+			fmt.Fprintf(out, "Cannot undo: %s\n", entry.Description)
+			entry.Status = JournalSkipped
+			continue
+		}
+
+		inverse, err := decodeAction(entry.Inverse)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		fmt.Fprintf(out, "Revert: %s\n", inverse.Description())
+		if err := inverse.Apply(t, ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%q: %w", entry.Description, err))
+			continue
+		}
+		entry.Status = JournalRolledBack
+	}
+
+	if err := j.save(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (j *Journal) save() error {
+	if j.path == "" {
+		journalDir, err := journalDir()
+		if err != nil {
+			return err
+		}
+		// The start time keeps the names sorted oldest first, the random suffix keeps runs that start at the same
+		// time from writing to the same file
+		file, err := os.CreateTemp(journalDir, fmt.Sprintf("%s-%s-*.json", j.BoardID, j.StartedAt.Format("20060102-150405.000000000")))
+		if err != nil {
+			return fmt.Errorf("could not create journal: %w", err)
+		}
+		file.Close()
+		j.path = file.Name()
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode journal: %w", err)
+	}
+
+	if err := os.WriteFile(j.path, data, 0600); err != nil {
+		return fmt.Errorf("could not write journal: %w", err)
+	}
+	return nil
+}
+
+func journalDir() (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	journalDir := filepath.Join(configDir, "journal")
+	if err := os.MkdirAll(journalDir, 0700); err != nil {
+		return "", fmt.Errorf("could not create journal directory: %w", err)
+	}
+
+	return journalDir, nil
+}
+
+func encodeAction(action TrelloAction) (*journalAction, error) {
+	actionType := reflect.TypeOf(action).Name()
+	if _, registered := journalActionTypes[actionType]; !registered {
+		return nil, fmt.Errorf("action type %s is not registered with the journal", actionType)
+	}
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode %s: %w", actionType, err)
+	}
+
+	return &journalAction{Type: actionType, Data: data}, nil
+}
+
+func decodeAction(encoded *journalAction) (TrelloAction, error) {
+	actionType, registered := journalActionTypes[encoded.Type]
+	if !registered {
+		return nil, fmt.Errorf("unknown action type %s in journal", encoded.Type)
+	}
+
+	action := reflect.New(actionType)
+	if err := json.Unmarshal(encoded.Data, action.Interface()); err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", encoded.Type, err)
+	}
+
+	return action.Elem().Interface().(TrelloAction), nil
+}
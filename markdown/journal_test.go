@@ -0,0 +1,175 @@
+package markdown
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/vinzmyko/mdello/config"
+	"github.com/vinzmyko/mdello/trello"
+	"github.com/vinzmyko/mdello/trello/trellotest"
+)
+
+// journalTestBoard is a fake trello board with the lists To Do and Done, and the card Fix login in To Do
+type journalTestBoard struct {
+	server *trellotest.Server
+	client *trello.TrelloClient
+	board  trello.Board
+	todo   trello.List
+	done   trello.List
+	card   trello.Card
+}
+
+func newJournalTestBoard(t *testing.T) *journalTestBoard {
+	t.Helper()
+	t.Setenv(config.EnvHome, t.TempDir())
+
+	server := trellotest.NewServer()
+	t.Cleanup(server.Close)
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	board := server.AddBoard("Project")
+	server.AddLabel(board.ID, "bug", "red")
+	todo := server.AddList(board.ID, "To Do")
+	return &journalTestBoard{
+		server: server,
+		client: client,
+		board:  board,
+		todo:   todo,
+		done:   server.AddList(board.ID, "Done"),
+		card:   server.AddCard(todo.ID, "Fix login"),
+	}
+}
+
+func (b *journalTestBoard) apply(t *testing.T, journal *Journal, actions ...TrelloAction) {
+	t.Helper()
+	ctx := NewActionContext(b.board.ID)
+	for _, action := range actions {
+		if err := journal.Apply(b.client, ctx, action); err != nil {
+			t.Fatalf("%s: %v", action.Description(), err)
+		}
+	}
+}
+
+// onlyCard returns the one card left on the board
+func (b *journalTestBoard) onlyCard(t *testing.T) trello.Card {
+	t.Helper()
+	cards := b.server.Snapshot(b.board.ID).Cards
+	if len(cards) != 1 {
+		t.Fatalf("the board has %d cards, want 1", len(cards))
+	}
+	return cards[0]
+}
+
+func TestRollbackUndoesAppliedChanges(t *testing.T) {
+	b := newJournalTestBoard(t)
+	journal := NewJournal(b.board.ID)
+	b.apply(t, journal,
+		UpdateCardNameAction{CardID: b.card.ID, OldName: "Fix login", NewName: "Fix logout"},
+		MoveCardAction{CardID: b.card.ID, Name: "Fix logout", FromList: b.todo.ID, ToList: b.done.ID},
+		AddCardLabelAction{CardID: b.card.ID, CardName: "Fix logout", LabelName: "bug"},
+	)
+
+	if err := journal.Rollback(b.client, io.Discard); err != nil {
+		t.Fatalf("Rollback() failed: %v", err)
+	}
+
+	card := b.onlyCard(t)
+	if card.Name != "Fix login" || card.IdList != b.todo.ID || len(card.IdLabels) != 0 {
+		t.Errorf("after the rollback the card is %q in list %s with labels %v, want %q in %s without labels",
+			card.Name, card.IdList, card.IdLabels, "Fix login", b.todo.ID)
+	}
+	for _, entry := range journal.Entries {
+		if entry.Status != JournalRolledBack {
+			t.Errorf("%s is %s, want rolled back", entry.Description, entry.Status)
+		}
+	}
+}
+
+func TestRollbackResolvesTheIDOfARecreatedCard(t *testing.T) {
+	b := newJournalTestBoard(t)
+	due := "2025-07-25T09:30:00.000Z"
+	journal := NewJournal(b.board.ID)
+	b.apply(t, journal,
+		AddCardLabelAction{CardID: b.card.ID, CardName: "Fix login", LabelName: "bug"},
+		UpdateCardDueDate{CardID: b.card.ID, Name: "Fix login", Due: due},
+		MoveCardAction{CardID: b.card.ID, Name: "Fix login", FromList: b.todo.ID, ToList: b.done.ID},
+		DeleteCardAction{CardID: b.card.ID, Name: "Fix login", ListID: b.done.ID, ListName: "Done", Labels: []string{"bug"}, Due: due},
+	)
+
+	// The card is recreated with a new ID, the older inverses still refer to the deleted one
+	if err := journal.Rollback(b.client, io.Discard); err != nil {
+		t.Fatalf("Rollback() failed: %v", err)
+	}
+
+	card := b.onlyCard(t)
+	if card.ID == b.card.ID {
+		t.Fatal("the deleted card was not recreated")
+	}
+	if card.IdList != b.todo.ID || len(card.IdLabels) != 0 || card.Due != nil {
+		t.Errorf("the recreated card is in list %s with labels %v and due %v, want %s without labels or due date",
+			card.IdList, card.IdLabels, card.Due, b.todo.ID)
+	}
+}
+
+func TestLoadLatestJournalStepsBackOneRunPerUndo(t *testing.T) {
+	b := newJournalTestBoard(t)
+
+	first := NewJournal(b.board.ID)
+	b.apply(t, first, UpdateCardNameAction{CardID: b.card.ID, OldName: "Fix login", NewName: "Fix logout"})
+	second := NewJournal(b.board.ID)
+	b.apply(t, second, UpdateCardNameAction{CardID: b.card.ID, OldName: "Fix logout", NewName: "Fix signup"})
+
+	for _, want := range []struct{ path, name string }{{second.Path(), "Fix logout"}, {first.Path(), "Fix login"}} {
+		journal, err := LoadLatestJournal(b.board.ID)
+		if err != nil {
+			t.Fatalf("LoadLatestJournal() failed: %v", err)
+		}
+		if journal.Path() != want.path {
+			t.Fatalf("LoadLatestJournal() loaded %s, want %s", journal.Path(), want.path)
+		}
+		if err := journal.Rollback(b.client, io.Discard); err != nil {
+			t.Fatalf("Rollback() failed: %v", err)
+		}
+		if card := b.onlyCard(t); card.Name != want.name {
+			t.Errorf("after undoing %s the card is %q, want %q", want.path, card.Name, want.name)
+		}
+	}
+
+	if _, err := LoadLatestJournal(b.board.ID); !errors.Is(err, ErrNoJournal) {
+		t.Errorf("LoadLatestJournal() after undoing every run = %v, want ErrNoJournal", err)
+	}
+}
+
+func TestJournalsStartedAtTheSameTimeKeepTheirOwnFiles(t *testing.T) {
+	t.Setenv(config.EnvHome, t.TempDir())
+
+	first := NewJournal("board")
+	second := NewJournal("board")
+	second.StartedAt = first.StartedAt
+	first.Entries = []JournalEntry{{Description: "first", Status: JournalApplied}}
+	second.Entries = []JournalEntry{{Description: "second", Status: JournalApplied}}
+
+	if err := first.save(); err != nil {
+		t.Fatalf("saving the first journal failed: %v", err)
+	}
+	if err := second.save(); err != nil {
+		t.Fatalf("saving the second journal failed: %v", err)
+	}
+	if first.Path() == second.Path() {
+		t.Fatalf("both journals were written to %s", first.Path())
+	}
+
+	for _, journal := range []*Journal{first, second} {
+		loaded, err := loadJournal(journal.Path())
+		if err != nil {
+			t.Fatalf("loadJournal() failed: %v", err)
+		}
+		if loaded.Entries[0].Description != journal.Entries[0].Description {
+			t.Errorf("%s holds %q, want %q", journal.Path(), loaded.Entries[0].Description, journal.Entries[0].Description)
+		}
+	}
+}
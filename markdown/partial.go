@@ -0,0 +1,77 @@
+package markdown
+
+import (
+	"errors"
+	"strings"
+)
+
+// SkipUnparsableLines finds every line of content that fails to parse. Each one is replaced by its version in
+// original, matched by the ID on the line, or blanked when the item is new, so the returned content parses and only
+// applies the lines that did. A blanked card takes its checklists with it. Line numbers in the errors match content
+// since no lines are added or removed
+func SkipUnparsableLines(content, original string, boardSession *BoardSession) (string, []*ParseError) {
+	lines := strings.Split(content, "\n")
+	var parseErrors []*ParseError
+
+	// Each failing line is tried once with its original version and once blanked
+	for range 2 * len(lines) {
+		_, err := FromMarkdown(strings.NewReader(strings.Join(lines, "\n")), boardSession)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || errors.Is(err, ErrUnresolvedConflict) {
+			break
+		}
+
+		failedLine := lines[parseErr.Line-1]
+		if len(parseErrors) > 0 && parseErrors[len(parseErrors)-1].Line == parseErr.Line {
+			// The original version of the line failed as well
+			blankBlock(lines, parseErr.Line-1)
+			continue
+		}
+
+		parseErrors = append(parseErrors, parseErr)
+		if replacement := originalLine(failedLine, original); replacement != "" {
+			lines[parseErr.Line-1] = replacement
+		} else {
+			blankBlock(lines, parseErr.Line-1)
+		}
+	}
+
+	return strings.Join(lines, "\n"), parseErrors
+}
+
+// originalLine returns the line in original with the same ID as line, or an empty line if it has none
+func originalLine(line, original string) string {
+	idMatch := cardIDRegex.FindString(line)
+	if idMatch == "" {
+		return ""
+	}
+
+	for _, candidate := range strings.Split(original, "\n") {
+		if strings.Contains(candidate, idMatch) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// blankBlock blanks the line at i and, when it is not indented, the indented lines below it. Checklists of a
+// blanked card would otherwise be read as checklists of the card above it
+func blankBlock(lines []string, i int) {
+	indented := isIndented(lines[i])
+	lines[i] = ""
+	if indented {
+		return
+	}
+	for j := i + 1; j < len(lines) && (strings.TrimSpace(lines[j]) == "" || isIndented(lines[j])); j++ {
+		lines[j] = ""
+	}
+}
+
+// ParseDiagnostics turns parse errors into diagnostics so they can be shown in the editor like validation problems
+func ParseDiagnostics(parseErrors []*ParseError) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(parseErrors))
+	for _, parseErr := range parseErrors {
+		diagnostics = append(diagnostics, Diagnostic{Line: parseErr.Line, Message: parseErr.Err.Error()})
+	}
+	return diagnostics
+}
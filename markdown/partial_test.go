@@ -0,0 +1,83 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vinzmyko/mdello/config"
+	"github.com/vinzmyko/mdello/trello"
+)
+
+func TestSkipUnparsableLinesBlanksNewCardsWithTheirChecklists(t *testing.T) {
+	session := newTestSession(t)
+	content := strings.Join([]string{
+		"# Board",
+		"",
+		"## Todo",
+		"- [ ] Write notes",
+		"- [ ] Fix @front end",
+		"  - Steps",
+		"",
+		"    - [ ] Reproduce",
+		"- [ ] Ship it",
+		"  - Release",
+	}, "\n")
+
+	parsed, parseErrors := SkipUnparsableLines(content, "", session)
+
+	if len(parseErrors) != 1 || parseErrors[0].Line != 5 {
+		t.Fatalf("got parse errors %v, want one on line 5", parseErrors)
+	}
+	want := strings.Join([]string{
+		"# Board",
+		"",
+		"## Todo",
+		"- [ ] Write notes",
+		"",
+		"",
+		"",
+		"",
+		"- [ ] Ship it",
+		"  - Release",
+	}, "\n")
+	if parsed != want {
+		t.Fatalf("SkipUnparsableLines() =\n%s\nwant:\n%s", parsed, want)
+	}
+
+	board, err := FromMarkdown(strings.NewReader(parsed), session)
+	if err != nil {
+		t.Fatalf("the skipped content does not parse: %v", err)
+	}
+	cards := board.Lists[0].Cards
+	if len(cards) != 2 || len(cards[0].Checklists) != 0 || len(cards[1].Checklists) != 1 {
+		t.Errorf("the checklists of the skipped card were kept: %+v", cards)
+	}
+}
+
+func TestSkipUnparsableLinesRestoresExistingCards(t *testing.T) {
+	t.Setenv(config.EnvHome, t.TempDir())
+	snapshot := &trello.BoardSnapshot{
+		Board: trello.Board{ID: "board", Name: "Board"},
+		Lists: []trello.List{{ID: "todo", Name: "Todo", IdBoard: "board"}},
+		Cards: []trello.Card{{ID: "card", Name: "Fix login", IdList: "todo"}},
+	}
+	session, err := NewBoardSession(snapshot)
+	if err != nil {
+		t.Fatalf("NewBoardSession() failed: %v", err)
+	}
+
+	listLine := fmt.Sprintf("## Todo {%s}", session.GetShortID("todo"))
+	cardID := session.GetShortID("card")
+	original := fmt.Sprintf("# Board {%s}\n\n%s\n- [ ] Fix login {%s}\n", session.GetShortID("board"), listLine, cardID)
+	// The checklist stays with the card, it is only the card line that failed
+	content := strings.Replace(original, "- [ ] Fix login", "- [ ] Fix login @front end", 1) + "  - Steps\n"
+
+	parsed, parseErrors := SkipUnparsableLines(content, original, session)
+	if len(parseErrors) != 1 || parseErrors[0].Line != 4 {
+		t.Fatalf("got parse errors %v, want one on line 4", parseErrors)
+	}
+	if want := original + "  - Steps\n"; parsed != want {
+		t.Errorf("SkipUnparsableLines() =\n%s\nwant:\n%s", parsed, want)
+	}
+}
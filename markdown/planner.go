@@ -0,0 +1,205 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Actions that create something declare what they provide and actions that refer to something declare what they
+// require, so the planner can order them without knowing every action type. Objects that already exist on trello
+// have no provider and never hold anything back
+
+var ErrDependencyCycle = errors.New("actions depend on each other")
+
+// dependent is implemented by actions that have to wait for, or be waited on by, other actions in the same run
+type dependent interface {
+	dependencies() (provides, requires []string)
+}
+
+func listKey(listID string) string     { return "list:" + listID }
+func cardKey(cardID string) string     { return "card:" + cardID }
+func labelKey(labelName string) string { return "label:" + markdownLabelName(labelName) }
+func checklistKey(id string) string    { return "checklist:" + id }
+func checkItemKey(id string) string    { return "checkItem:" + id }
+
+// unlabelKey is provided by removing a label from a card, which finds the label by the name it had before the run
+func unlabelKey(labelName string) string { return "unlabel:" + markdownLabelName(labelName) }
+
+// freedLabelKey is provided by renaming or deleting a label, after which its old name can be given to a new label
+func freedLabelKey(labelName string) string { return "freed:" + markdownLabelName(labelName) }
+
+// Dependencies returns what an action provides to and requires from other actions in the same run
+func Dependencies(action TrelloAction) (provides, requires []string) {
+	dependentAction, ok := action.(dependent)
+	if !ok {
+		return nil, nil
+	}
+	return dependentAction.dependencies()
+}
+
+// Plan is the order actions are applied in, split into stages. A stage only depends on the stages before it, so
+// the actions within a stage do not depend on each other and can be applied in parallel
+type Plan [][]TrelloAction
+
+// PlanActions orders actions so everything an action refers to is created first. The order is stable: actions keep
+// the order they were given in unless a dependency moves them to a later stage
+func PlanActions(actions []TrelloAction) (Plan, error) {
+	providers := make(map[string][]int)
+	requires := make([][]string, len(actions))
+	for i, action := range actions {
+		provides, required := Dependencies(action)
+		for _, key := range provides {
+			providers[key] = append(providers[key], i)
+		}
+		requires[i] = required
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(actions))
+	stages := make([]int, len(actions))
+
+	// An action's stage is one after the latest stage of the actions it requires
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, actions[i].Description())
+		}
+
+		state[i] = visiting
+		for _, key := range requires[i] {
+			for _, provider := range providers[key] {
+				if provider == i {
+					continue
+				}
+				if err := visit(provider); err != nil {
+					return err
+				}
+				stages[i] = max(stages[i], stages[provider]+1)
+			}
+		}
+		state[i] = done
+		return nil
+	}
+
+	plan := Plan{}
+	for i := range actions {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+		for len(plan) <= stages[i] {
+			plan = append(plan, nil)
+		}
+	}
+	for i, action := range actions {
+		plan[stages[i]] = append(plan[stages[i]], action)
+	}
+
+	return plan, nil
+}
+
+// Actions returns every action in the plan in the order it is applied
+func (p Plan) Actions() []TrelloAction {
+	var actions []TrelloAction
+	for _, stage := range p {
+		actions = append(actions, stage...)
+	}
+	return actions
+}
+
+func (act CreateLabelAction) dependencies() (provides, requires []string) {
+	return []string{labelKey(act.Name)}, []string{freedLabelKey(act.Name)}
+}
+
+// Cards refer to labels by name, so a card using the new name has to wait for the rename and the rename has to
+// wait for the cards dropping the label by its old name
+func (act UpdateLabelName) dependencies() (provides, requires []string) {
+	return []string{labelKey(act.NewName), freedLabelKey(act.OldName)}, []string{unlabelKey(act.OldName)}
+}
+
+func (act DeleteLabelAction) dependencies() (provides, requires []string) {
+	return []string{freedLabelKey(act.Name)}, []string{unlabelKey(act.Name)}
+}
+
+func (act CreateListAction) dependencies() (provides, requires []string) {
+	return []string{listKey(act.ListID)}, nil
+}
+
+func (act UpdateListNameAction) dependencies() (provides, requires []string) {
+	return nil, []string{listKey(act.ListID)}
+}
+
+func (act UpdateListPositionAction) dependencies() (provides, requires []string) {
+	return nil, []string{listKey(act.ListID)}
+}
+
+func (act ArchiveListAction) dependencies() (provides, requires []string) {
+	return nil, []string{listKey(act.ListID)}
+}
+
+// A new card is created with its labels, so they have to exist first
+func (act CreateCardAction) dependencies() (provides, requires []string) {
+	requires = []string{listKey(act.ListID)}
+	for _, labelName := range act.Labels {
+		requires = append(requires, labelKey(labelName))
+	}
+	return []string{cardKey(act.CardID)}, requires
+}
+
+func (act MoveCardAction) dependencies() (provides, requires []string) {
+	return nil, []string{cardKey(act.CardID), listKey(act.ToList)}
+}
+
+func (act UpdateCardNameAction) dependencies() (provides, requires []string) {
+	return nil, []string{cardKey(act.CardID)}
+}
+
+func (act UpdateCardPositionAction) dependencies() (provides, requires []string) {
+	return nil, []string{cardKey(act.CardID)}
+}
+
+func (act UpdateCardIsCompletedAction) dependencies() (provides, requires []string) {
+	return nil, []string{cardKey(act.CardID)}
+}
+
+func (act AddCardLabelAction) dependencies() (provides, requires []string) {
+	return nil, []string{cardKey(act.CardID), labelKey(act.LabelName)}
+}
+
+func (act DeleteCardLabelAction) dependencies() (provides, requires []string) {
+	return []string{unlabelKey(act.LabelName)}, []string{cardKey(act.CardID)}
+}
+
+func (act UpdateCardDueDate) dependencies() (provides, requires []string) {
+	return nil, []string{cardKey(act.CardID)}
+}
+
+func (act DeleteCardDueDate) dependencies() (provides, requires []string) {
+	return nil, []string{cardKey(act.CardID)}
+}
+
+func (act CreateChecklistAction) dependencies() (provides, requires []string) {
+	return []string{checklistKey(act.ChecklistID)}, []string{cardKey(act.CardID)}
+}
+
+func (act UpdateChecklistNameAction) dependencies() (provides, requires []string) {
+	return nil, []string{checklistKey(act.ChecklistID)}
+}
+
+func (act CreateCheckItemAction) dependencies() (provides, requires []string) {
+	return []string{checkItemKey(act.CheckItemID)}, []string{checklistKey(act.ChecklistID)}
+}
+
+func (act UpdateCheckItemNameAction) dependencies() (provides, requires []string) {
+	return nil, []string{checkItemKey(act.CheckItemID)}
+}
+
+func (act UpdateCheckItemStateAction) dependencies() (provides, requires []string) {
+	return nil, []string{checkItemKey(act.CheckItemID)}
+}
@@ -0,0 +1,114 @@
+package markdown
+
+import (
+	"slices"
+	"testing"
+)
+
+// descriptions lists each stage of the plan by the descriptions of its actions
+func descriptions(plan Plan) [][]string {
+	stages := make([][]string, len(plan))
+	for i, stage := range plan {
+		for _, action := range stage {
+			stages[i] = append(stages[i], action.Description())
+		}
+	}
+	return stages
+}
+
+func TestPlanActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []TrelloAction
+		want    [][]string
+	}{
+		{
+			name: "actions on existing objects share one stage",
+			actions: []TrelloAction{
+				UpdateListNameAction{ListID: "list", OldName: "Todo", NewName: "Doing"},
+				UpdateCardNameAction{CardID: "card", OldName: "Old", NewName: "New"},
+			},
+			want: [][]string{{
+				`List "Todo" renamed to "Doing"`,
+				`Card "Old" renamed to "New"`,
+			}},
+		},
+		{
+			name: "a new card waits for its new list and label",
+			actions: []TrelloAction{
+				CreateCardAction{CardID: "card", ListID: "list", Name: "Task", Labels: []string{"bug"}},
+				CreateListAction{ListID: "list", Name: "Todo"},
+				CreateLabelAction{LabelID: "label", Name: "bug"},
+			},
+			want: [][]string{
+				{`Created list "Todo" at position 0`, `Created label "bug" with colour ""`},
+				{`Create card "Task" at position 0`},
+			},
+		},
+		{
+			name: "check items wait for their checklist, which waits for its card",
+			actions: []TrelloAction{
+				CreateCheckItemAction{CheckItemID: "item", ChecklistID: "checklist", ChecklistName: "Steps", Name: "One"},
+				CreateChecklistAction{ChecklistID: "checklist", CardID: "card", CardName: "Task", Name: "Steps"},
+				CreateCardAction{CardID: "card", ListID: "list", Name: "Task"},
+			},
+			want: [][]string{
+				{`Create card "Task" at position 0`},
+				{`Created checklist "Steps" with 0 item(s) on card "Task"`},
+				{`Added item "One" to checklist "Steps"`},
+			},
+		},
+		{
+			name: "a renamed label is removed from cards by its old name before the rename",
+			actions: []TrelloAction{
+				UpdateLabelName{ID: "label", OldName: "bug", NewName: "defect"},
+				DeleteCardLabelAction{CardID: "one", CardName: "One", LabelName: "bug"},
+				AddCardLabelAction{CardID: "two", CardName: "Two", LabelName: "defect"},
+			},
+			want: [][]string{
+				{`Removed label "bug" from card "One"`},
+				{`Label "bug" renamed to "defect"`},
+				{`Added label "defect" to card "Two"`},
+			},
+		},
+		{
+			name: "a deleted label is removed from cards before it is deleted",
+			actions: []TrelloAction{
+				DeleteLabelAction{ID: "label", Name: "bug"},
+				DeleteCardLabelAction{CardID: "one", CardName: "One", LabelName: "bug"},
+			},
+			want: [][]string{
+				{`Removed label "bug" from card "One"`},
+				{`Label "bug" deleted`},
+			},
+		},
+		{
+			name: "a new label taking a renamed label's name waits for the rename",
+			actions: []TrelloAction{
+				UpdateLabelName{ID: "label", OldName: "bug", NewName: "defect"},
+				DeleteCardLabelAction{CardID: "one", CardName: "One", LabelName: "bug"},
+				CreateLabelAction{LabelID: "new", Name: "bug"},
+				AddCardLabelAction{CardID: "two", CardName: "Two", LabelName: "bug"},
+			},
+			want: [][]string{
+				{`Removed label "bug" from card "One"`},
+				{`Label "bug" renamed to "defect"`},
+				{`Created label "bug" with colour ""`},
+				{`Added label "bug" to card "Two"`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan, err := PlanActions(test.actions)
+			if err != nil {
+				t.Fatalf("PlanActions() failed: %v", err)
+			}
+			got := descriptions(plan)
+			if !slices.EqualFunc(got, test.want, slices.Equal) {
+				t.Errorf("PlanActions() stages:\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+}
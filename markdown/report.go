@@ -0,0 +1,119 @@
+package markdown
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ApplyReport records the outcome of every action applied in a run, it is shown as a table or written as JSON
+type ApplyReport struct {
+	BoardID string         `json:"boardId"`
+	Results []ActionResult `json:"actions"`
+	mu      sync.Mutex     // Actions applied in parallel record their results one at a time
+}
+
+type ActionResult struct {
+	Type        string        `json:"type"`
+	Description string        `json:"description"`
+	Status      JournalStatus `json:"status"`
+	ObjectID    string        `json:"objectId,omitempty"` // Trello ID of the object the action touched
+	DurationMs  int64         `json:"durationMs"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// The most specific ID an action carries is the object it touches, e.g. a check item rather than its card
+var objectIDFields = []string{"CheckItemID", "ChecklistID", "CardID", "ListID", "LabelID", "ID", "BoardID"}
+
+func NewApplyReport(boardID string) *ApplyReport {
+	return &ApplyReport{BoardID: boardID, Results: []ActionResult{}}
+}
+
+// Record adds the outcome of an applied action. It is called after Apply so created IDs can be resolved
+func (r *ApplyReport) Record(action TrelloAction, ctx *ActionContext, duration time.Duration, err error) {
+	result := ActionResult{
+		Type:        reflect.TypeOf(action).Name(),
+		Description: action.Description(),
+		Status:      JournalApplied,
+		ObjectID:    objectID(action, ctx),
+		DurationMs:  duration.Milliseconds(),
+	}
+	if err != nil {
+		result.Status = JournalFailed
+		result.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Results = append(r.Results, result)
+}
+
+// RecordSkipped adds an action that was not applied because an action it depends on failed
+func (r *ApplyReport) RecordSkipped(action TrelloAction, ctx *ActionContext, reason error) {
+	result := ActionResult{
+		Type:        reflect.TypeOf(action).Name(),
+		Description: action.Description(),
+		Status:      JournalSkipped,
+		ObjectID:    objectID(action, ctx),
+		Error:       reason.Error(),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Results = append(r.Results, result)
+}
+
+// Retry drops the failed and skipped results, resuming a run applies every one of those actions again
+func (r *ApplyReport) Retry() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := r.Results[:0]
+	for _, result := range r.Results {
+		if result.Status != JournalFailed && result.Status != JournalSkipped {
+			results = append(results, result)
+		}
+	}
+	r.Results = results
+}
+
+// RolledBack marks every applied action as rolled back
+func (r *ApplyReport) RolledBack() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.Results {
+		if r.Results[i].Status == JournalApplied {
+			r.Results[i].Status = JournalRolledBack
+		}
+	}
+}
+
+// Count returns how many actions ended with the status
+func (r *ApplyReport) Count(status JournalStatus) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func objectID(action TrelloAction, ctx *ActionContext) string {
+	value := reflect.ValueOf(action)
+	for _, fieldName := range objectIDFields {
+		field := value.FieldByName(fieldName)
+		if !field.IsValid() || field.Kind() != reflect.String || field.String() == "" {
+			continue
+		}
+
+		// Created objects only have a sentinel ID until they exist
+		id, err := ctx.ResolveID(field.String())
+		if err != nil {
+			return ""
+		}
+		return id
+	}
+	return ""
+}
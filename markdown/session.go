@@ -13,21 +13,50 @@ const SHORT_ID_LENGTH = 5
 type BoardSession struct {
 	board          *trello.Board
 	idMapper       *idMapper
+	items          map[string]sessionItem
 	newItemCounter int
 }
 
-func NewBoardSession(board *trello.Board, trelloClient *trello.TrelloClient) (*BoardSession, error) {
+type itemKind int
+
+const (
+	kindLabel itemKind = iota
+	kindList
+	kindCard
+	kindChecklist
+	kindCheckItem
+)
+
+// sessionItem is what the session knows about an existing trello object, used to match lines that lost their ID
+type sessionItem struct {
+	kind     itemKind
+	name     string
+	parentID string
+	pos      float64
+}
+
+func NewBoardSession(snapshot *trello.BoardSnapshot) (*BoardSession, error) {
+	storedMappings, err := loadIDMappings(snapshot.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	session := &BoardSession{
-		board:          board,
+		board:          &snapshot.Board,
 		newItemCounter: 0,
-		idMapper: &idMapper{
-			shortToFull: make(map[string]string),
-			fullToShort: make(map[string]string),
-		},
+		idMapper:       newIDMapper(storedMappings),
+		items:          make(map[string]sessionItem),
 	}
 
-	err := session.buildIDMapping(trelloClient)
-	return session, err
+	session.buildIDMapping(snapshot)
+
+	if session.idMapper.changed {
+		if err := saveIDMappings(snapshot.ID, session.idMapper.shortToFull); err != nil {
+			return session, err
+		}
+	}
+
+	return session, nil
 }
 
 func (s *BoardSession) GetShortID(fullID string) string {
@@ -35,6 +64,10 @@ func (s *BoardSession) GetShortID(fullID string) string {
 }
 
 func (s *BoardSession) IsSentinelID(id string) bool {
+	return IsSentinelID(id)
+}
+
+func IsSentinelID(id string) bool {
 	return strings.HasPrefix(id, "NEW_ITEM_")
 }
 
@@ -50,50 +83,106 @@ func (s *BoardSession) ResolveShortID(shortID string) (string, error) {
 	return "", fmt.Errorf("short ID %s not found", shortID)
 }
 
-func (s *BoardSession) buildIDMapping(trelloClient *trello.TrelloClient) error {
-	s.idMapper.addMapping(s.board.ID)
+func (s *BoardSession) buildIDMapping(snapshot *trello.BoardSnapshot) {
+	s.idMapper.addMapping(snapshot.ID)
 
-	labels, err := trelloClient.GetBoardLabels(s.board.ID)
-	if err != nil {
-		return err
+	for _, label := range snapshot.Labels {
+		s.addItem(label.ID, kindLabel, strings.ReplaceAll(label.Name, " ", "~"), snapshot.ID, 0)
 	}
-	for _, label := range labels {
-		s.idMapper.addMapping(label.ID)
+
+	for _, list := range snapshot.Lists {
+		s.addItem(list.ID, kindList, list.Name, snapshot.ID, list.Pos)
 	}
 
-	lists, err := trelloClient.GetLists(s.board.ID)
-	if err != nil {
-		return err
+	for _, card := range snapshot.Cards {
+		s.addItem(card.ID, kindCard, card.Name, card.IdList, card.Pos)
 	}
 
-	for _, list := range lists {
-		s.idMapper.addMapping(list.ID)
+	for _, checklist := range snapshot.Checklists {
+		s.addItem(checklist.ID, kindChecklist, checklist.Name, checklist.IdCard, checklist.Pos)
 
-		cards, err := trelloClient.GetCards(list.ID)
-		if err != nil {
-			return err
+		for _, checkItem := range checklist.CheckItems {
+			s.addItem(checkItem.ID, kindCheckItem, checkItem.Name, checklist.ID, checkItem.Pos)
 		}
+	}
+}
 
-		for _, card := range cards {
-			s.idMapper.addMapping(card.ID)
+func (s *BoardSession) addItem(fullID string, kind itemKind, name, parentID string, pos float64) {
+	s.idMapper.addMapping(fullID)
+	s.items[fullID] = sessionItem{
+		kind:     kind,
+		name:     name,
+		parentID: parentID,
+		pos:      pos,
+	}
+}
+
+// trelloPos is the position trello had for an existing item when the session was created
+func (s *BoardSession) trelloPos(fullID string) float64 {
+	return s.items[fullID].pos
+}
+
+// matchExistingItem finds the existing item a line without an ID most likely refers to. Only a single unused
+// item with the same name counts as a match, anything ambiguous is treated as a new item
+func (s *BoardSession) matchExistingItem(kind itemKind, name, parentID string, usedIDs map[string]bool) (string, bool) {
+	var matchedID string
+	matches := 0
+
+	for fullID, item := range s.items {
+		if item.kind != kind || item.name != name || usedIDs[fullID] {
+			continue
+		}
+		if parentID != "" && item.parentID != parentID {
+			continue
 		}
+		matchedID = fullID
+		matches++
 	}
 
-	return nil
+	return matchedID, matches == 1
 }
 
 type idMapper struct {
 	shortToFull map[string]string
 	fullToShort map[string]string
+	changed     bool
+}
+
+func newIDMapper(storedMappings map[string]string) *idMapper {
+	mapper := &idMapper{
+		shortToFull: make(map[string]string),
+		fullToShort: make(map[string]string),
+	}
+
+	for shortID, fullID := range storedMappings {
+		mapper.shortToFull[shortID] = fullID
+		mapper.fullToShort[fullID] = shortID
+	}
+
+	return mapper
 }
 
+// addMapping keeps short IDs stable once assigned. On a collision the hash prefix is lengthened until it is unique
 func (m *idMapper) addMapping(fullID string) {
-	shortID := generateShortID(fullID)
+	if _, exists := m.fullToShort[fullID]; exists {
+		return
+	}
+
+	hash := generateShortID(fullID)
+	shortID := hash[:SHORT_ID_LENGTH]
+	for length := SHORT_ID_LENGTH + 1; length <= len(hash); length++ {
+		if _, taken := m.shortToFull[shortID]; !taken {
+			break
+		}
+		shortID = hash[:length]
+	}
+
 	m.shortToFull[shortID] = fullID
 	m.fullToShort[fullID] = shortID
+	m.changed = true
 }
 
 func generateShortID(trelloID string) string {
 	hash := sha256.Sum256([]byte(trelloID))
-	return fmt.Sprintf("%x", hash)[:SHORT_ID_LENGTH]
+	return fmt.Sprintf("%x", hash)
 }
@@ -2,6 +2,8 @@ package markdown
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -9,27 +11,49 @@ import (
 	"github.com/vinzmyko/mdello/trello"
 )
 
-func ToMarkdown(trelloClient *trello.TrelloClient, configuration *config.Config, board *trello.Board) (string, *BoardSession, error) {
-	session, err := NewBoardSession(board, trelloClient)
+func ToMarkdown(configuration *config.Config, snapshot *trello.BoardSnapshot) (string, *BoardSession, error) {
+	session, err := NewBoardSession(snapshot)
 	if err != nil {
 		return "", nil, err
 	}
+	board := snapshot.Board
 
 	var markdown strings.Builder
 
+	// Hidden labels are missing from both the original and the edited markdown, so they are never diffed
+	showLabels := configuration == nil || configuration.LabelDisplay != config.LabelDisplayHidden
+
 	markdown.WriteString(fmt.Sprintf("# %s {%s}\n", board.Name, session.GetShortID(board.ID)))
 	for _, label := range board.Labels {
-		if label.Name == "" {
+		if label.Name == "" || !showLabels {
 			continue
 		}
 		markdownLabelName := strings.ReplaceAll(label.Name, " ", "~")
 		markdown.WriteString(fmt.Sprintf("@%s:%s {%s}\n", markdownLabelName, label.Colour, session.GetShortID(label.ID)))
 	}
 
-	lists, _ := trelloClient.GetLists(board.ID)
+	checklistsByCard := make(map[string][]trello.Checklist)
+	for _, checklist := range snapshot.Checklists {
+		checklistsByCard[checklist.IdCard] = append(checklistsByCard[checklist.IdCard], checklist)
+	}
+
+	cardsByList := make(map[string][]trello.Card)
+	for _, card := range snapshot.Cards {
+		cardsByList[card.IdList] = append(cardsByList[card.IdList], card)
+	}
+
+	lists := snapshot.Lists
+	sort.SliceStable(lists, func(i, j int) bool {
+		return lists[i].Pos < lists[j].Pos
+	})
+
 	for _, list := range lists {
 		markdown.WriteString(fmt.Sprintf("\n## %s {%s}", list.Name, session.GetShortID(list.ID)))
-		cards, _ := trelloClient.GetCards(list.ID)
+
+		cards := cardsByList[list.ID]
+		sort.SliceStable(cards, func(i, j int) bool {
+			return cards[i].Pos < cards[j].Pos
+		})
 
 		for _, card := range cards {
 			var checkbox string
@@ -40,6 +64,9 @@ func ToMarkdown(trelloClient *trello.TrelloClient, configuration *config.Config,
 			}
 			var labels strings.Builder
 			for _, label := range card.Labels {
+				if !showLabels {
+					break
+				}
 				markdownLabel := strings.ReplaceAll(label.Name, " ", "~")
 				labels.WriteString(fmt.Sprintf(" @%s", markdownLabel))
 			}
@@ -50,6 +77,8 @@ func ToMarkdown(trelloClient *trello.TrelloClient, configuration *config.Config,
 
 			markdown.WriteString(fmt.Sprintf("\n- %s %s%s%s {%s}",
 				checkbox, card.Name, labels.String(), dueDateStr, session.GetShortID(card.ID)))
+
+			markdown.WriteString(checklistsToMarkdown(checklistsByCard[card.ID], session))
 		}
 		markdown.WriteString("\n")
 	}
@@ -57,7 +86,35 @@ func ToMarkdown(trelloClient *trello.TrelloClient, configuration *config.Config,
 	return markdown.String(), session, nil
 }
 
-func GenerateDetailedMarkdown(detailedActions []DetailedTrelloAction, trelloClient *trello.TrelloClient, cfg *config.Config) (string, error) {
+// Checklists are nested under their card, each check item is indented below its checklist name
+func checklistsToMarkdown(checklists []trello.Checklist, session *BoardSession) string {
+	var markdown strings.Builder
+
+	sort.SliceStable(checklists, func(i, j int) bool {
+		return checklists[i].Pos < checklists[j].Pos
+	})
+
+	for _, checklist := range checklists {
+		markdown.WriteString(fmt.Sprintf("\n  - %s {%s}", checklist.Name, session.GetShortID(checklist.ID)))
+
+		checkItems := checklist.CheckItems
+		sort.SliceStable(checkItems, func(i, j int) bool {
+			return checkItems[i].Pos < checkItems[j].Pos
+		})
+
+		for _, checkItem := range checkItems {
+			checkbox := "[ ]"
+			if checkItem.State == "complete" {
+				checkbox = "[x]"
+			}
+			markdown.WriteString(fmt.Sprintf("\n    - %s %s {%s}", checkbox, checkItem.Name, session.GetShortID(checkItem.ID)))
+		}
+	}
+
+	return markdown.String()
+}
+
+func GenerateDetailedMarkdown(detailedActions []DetailedTrelloAction, trelloClient trello.API, cfg *config.Config) (string, error) {
 	var content strings.Builder
 	for _, detailedAction := range detailedActions {
 		switch detailedAction.ObjectType {
@@ -84,7 +141,7 @@ func GenerateDetailedMarkdown(detailedActions []DetailedTrelloAction, trelloClie
 	return content.String(), nil
 }
 
-func GenerateDetailedBoardContent(action DetailedTrelloAction, trelloClient *trello.TrelloClient) (string, error) {
+func GenerateDetailedBoardContent(action DetailedTrelloAction, trelloClient trello.API) (string, error) {
 	var content strings.Builder
 
 	board, err := trelloClient.GetBoard(string(action.ObjectID))
@@ -122,7 +179,7 @@ func GenerateDetailedBoardContent(action DetailedTrelloAction, trelloClient *tre
 	return content.String(), nil
 }
 
-func GenerateDetailedListContent(action DetailedTrelloAction, trelloClient *trello.TrelloClient) (string, error) {
+func GenerateDetailedListContent(action DetailedTrelloAction, trelloClient trello.API) (string, error) {
 	var content strings.Builder
 
 	list, err := trelloClient.GetList(string(action.ObjectID))
@@ -143,7 +200,7 @@ func GenerateDetailedListContent(action DetailedTrelloAction, trelloClient *trel
 	return content.String(), nil
 }
 
-func GenerateDetailedCardContent(action DetailedTrelloAction, trelloClient *trello.TrelloClient, cfg *config.Config) (string, error) {
+func GenerateDetailedCardContent(action DetailedTrelloAction, trelloClient trello.API, cfg *config.Config) (string, error) {
 	var content strings.Builder
 
 	card, err := trelloClient.GetCard(string(action.ObjectID))
@@ -200,18 +257,25 @@ func generateSectionHeader(title, objectType, objectID string) string {
 func formatDate(due string, configuration *config.Config) string {
 	parsedTime, err := time.Parse(time.RFC3339, due)
 	if err != nil {
-		fmt.Println("\nError parsing due date: skipping")
+		fmt.Fprintln(os.Stderr, "\nError parsing due date: skipping")
 		return ""
 	}
 
-	return parsedTime.Local().Format(configuration.DateFormat)
+	// Actions read back from a journal have no config
+	dateFormat := config.DateFormatISO
+	location := time.Local
+	if configuration != nil {
+		dateFormat = configuration.DateLayout()
+		location = configuration.Location()
+	}
+	return parsedTime.In(location).Format(dateFormat)
 }
 
 func ParseMarkdownDate(dateStr string, configuration *config.Config) (string, error) {
-	parsedTime, err := time.ParseInLocation(configuration.DateFormat, dateStr, time.Local)
+	parsedTime, err := time.ParseInLocation(configuration.DateLayout(), dateStr, configuration.Location())
 	if err != nil {
 		return "", fmt.Errorf("failed to parse date %s with format %s: %w",
-			dateStr, configuration.DateFormat, err)
+			dateStr, configuration.DateLayout(), err)
 	}
 
 	return parsedTime.UTC().Format(time.RFC3339), nil
@@ -0,0 +1,40 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/vinzmyko/mdello/config"
+)
+
+func TestDueDatesRoundTrip(t *testing.T) {
+	const due = "2025-07-25T08:30:00Z"
+
+	tests := []struct {
+		name      string
+		profile   config.Profile
+		formatted string
+	}{
+		{"no date format reads and writes ISO", config.Profile{TimeZone: "UTC"}, "2025-07-25 08:30"},
+		{"a date format", config.Profile{DateFormat: config.DateFormatEU, TimeZone: "UTC"}, "25-07-2025 08:30"},
+		{"a time zone", config.Profile{DateFormat: config.DateFormatUS, TimeZone: "Australia/Sydney"}, "07-25-2025 18:30"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &config.Config{Profile: test.profile}
+
+			formatted := formatDate(due, cfg)
+			if formatted != test.formatted {
+				t.Errorf("formatDate() = %q, want %q", formatted, test.formatted)
+			}
+
+			parsed, err := ParseMarkdownDate(formatted, cfg)
+			if err != nil {
+				t.Fatalf("ParseMarkdownDate() failed: %v", err)
+			}
+			if parsed != due {
+				t.Errorf("ParseMarkdownDate() = %q, want %q", parsed, due)
+			}
+		})
+	}
+}
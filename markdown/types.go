@@ -13,6 +13,7 @@ type ParsedBoard struct {
 	Name         string
 	Lists        []*ParsedList
 	Labels       []*trello.Label
+	LabelLines   []int // Line each label was read from, in the same order as Labels
 	DetailedEdit bool
 }
 
@@ -20,6 +21,8 @@ type ParsedList struct {
 	ID           string
 	Name         string
 	MarkdownIdx  int
+	TrelloPos    float64 // Position on trello, 0 for lists that don't exist yet
+	Line         int     // Line in the markdown, for diagnostics
 	Cards        []*ParsedCard
 	DetailedEdit bool
 }
@@ -29,12 +32,31 @@ type ParsedCard struct {
 	ListID       string
 	Name         string
 	Position     int
+	TrelloPos    float64 // Position on trello in the card's original list, 0 for cards that don't exist yet
+	Line         int     // Line in the markdown, for diagnostics
 	IsComplete   string
 	Labels       []string
 	DueDate      string
+	Checklists   []*ParsedChecklist
 	DetailedEdit bool
 }
 
+type ParsedChecklist struct {
+	ID         string
+	CardID     string
+	Name       string
+	Position   int
+	CheckItems []*ParsedCheckItem
+}
+
+type ParsedCheckItem struct {
+	ID          string
+	ChecklistID string
+	Name        string
+	Position    int
+	IsComplete  bool
+}
+
 type DiffResult struct {
 	QuickActions    []TrelloAction
 	DetailedActions []DetailedTrelloAction
@@ -0,0 +1,187 @@
+package markdown
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vinzmyko/mdello/config"
+)
+
+// Edits are validated between parsing and applying, so problems that would otherwise fail halfway through applying
+// are all reported up front, each with the line it was found on
+
+const diagnosticPrefix = "<!-- mdello: "
+
+// Colours trello accepts for labels
+var labelColours = map[string]bool{
+	"green": true, "yellow": true, "orange": true, "red": true, "purple": true,
+	"blue": true, "sky": true, "lime": true, "pink": true, "black": true,
+}
+
+type Diagnostic struct {
+	Line    int
+	Message string
+	Hint    string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("line %d: %s", d.Line, d.text())
+}
+
+func (d Diagnostic) text() string {
+	if d.Hint == "" {
+		return d.Message
+	}
+	return d.Message + ". " + d.Hint
+}
+
+// ValidationError holds every problem found in an edit, sorted by line
+type ValidationError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Diagnostics)+1)
+	lines = append(lines, fmt.Sprintf("%d problem(s) found:", len(e.Diagnostics)))
+	for _, diagnostic := range e.Diagnostics {
+		lines = append(lines, "  "+diagnostic.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ValidateBoard checks an edited board for problems trello would reject, it returns a *ValidationError
+// listing all of them or nil
+func ValidateBoard(board *ParsedBoard, cfg *config.Config) error {
+	var diagnostics []Diagnostic
+
+	labelNames := make(map[string]bool)
+	for i, label := range board.Labels {
+		line := labelLine(board, i)
+		name := markdownLabelName(label.Name)
+
+		if labelNames[name] {
+			diagnostics = append(diagnostics, Diagnostic{
+				Line:    line,
+				Message: fmt.Sprintf("label '%s' is defined more than once", name),
+				Hint:    "Cards refer to labels by name, rename or remove one of them",
+			})
+		} else {
+			labelNames[name] = true
+		}
+
+		if !isLabelColour(label.Colour) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Line:    line,
+				Message: fmt.Sprintf("'%s' is not a label colour", label.Colour),
+				Hint:    "Use one of green, yellow, orange, red, purple, blue, sky, lime, pink or black, optionally with _dark or _light",
+			})
+		}
+	}
+
+	listNames := make(map[string]bool)
+	for _, list := range board.Lists {
+		if listNames[list.Name] {
+			diagnostics = append(diagnostics, Diagnostic{
+				Line:    list.Line,
+				Message: fmt.Sprintf("list '%s' is defined more than once", list.Name),
+				Hint:    "List names must be unique, rename one of them",
+			})
+		} else {
+			listNames[list.Name] = true
+		}
+
+		for _, card := range list.Cards {
+			diagnostics = append(diagnostics, validateCard(card, labelNames, cfg)...)
+		}
+	}
+
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Line < diagnostics[j].Line
+	})
+	return &ValidationError{Diagnostics: diagnostics}
+}
+
+func validateCard(card *ParsedCard, labelNames map[string]bool, cfg *config.Config) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	for _, labelName := range card.Labels {
+		if !labelNames[labelName] {
+			diagnostics = append(diagnostics, Diagnostic{
+				Line:    card.Line,
+				Message: fmt.Sprintf("label '%s' does not exist on the board", labelName),
+				Hint:    fmt.Sprintf("Add it under the board heading, e.g. @%s:green, or fix the spelling", labelName),
+			})
+		}
+	}
+
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	dateFormat := cfg.DateLayout()
+	if card.DueDate != "" {
+		if _, err := ParseMarkdownDate(card.DueDate, cfg); err != nil {
+			diagnostics = append(diagnostics, Diagnostic{
+				Line:    card.Line,
+				Message: fmt.Sprintf("due date '%s' could not be read", card.DueDate),
+				Hint:    fmt.Sprintf("Write it in the configured format %s", dateFormat),
+			})
+		}
+	} else if strings.Contains(card.Name, "due:") {
+		// The due regex only matches digits, anything else is left in the card name
+		diagnostics = append(diagnostics, Diagnostic{
+			Line:    card.Line,
+			Message: "due date could not be read",
+			Hint:    fmt.Sprintf("Write it in the configured format %s", dateFormat),
+		})
+	}
+
+	return diagnostics
+}
+
+func isLabelColour(colour string) bool {
+	colour = strings.TrimSuffix(strings.TrimSuffix(colour, "_dark"), "_light")
+	return labelColours[colour]
+}
+
+func labelLine(board *ParsedBoard, i int) int {
+	if i < len(board.LabelLines) {
+		return board.LabelLines[i]
+	}
+	return 0
+}
+
+// InsertDiagnostics adds each diagnostic as a comment above the line it was found on, so the problems can be
+// fixed in the editor. The content must not contain comments from an earlier attempt or the lines will not match
+func InsertDiagnostics(content string, diagnostics []Diagnostic) string {
+	byLine := make(map[int][]Diagnostic)
+	for _, diagnostic := range diagnostics {
+		byLine[diagnostic.Line] = append(byLine[diagnostic.Line], diagnostic)
+	}
+
+	lines := strings.Split(content, "\n")
+	result := make([]string, 0, len(lines)+len(diagnostics))
+	for i, line := range lines {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		for _, diagnostic := range byLine[i+1] {
+			result = append(result, fmt.Sprintf("%s%s%s -->", indent, diagnosticPrefix, diagnostic.text()))
+		}
+		result = append(result, line)
+	}
+	return strings.Join(result, "\n")
+}
+
+// StripDiagnostics removes the comments added by InsertDiagnostics
+func StripDiagnostics(content string) string {
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), diagnosticPrefix) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
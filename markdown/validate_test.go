@@ -0,0 +1,119 @@
+package markdown
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vinzmyko/mdello/config"
+	"github.com/vinzmyko/mdello/trello"
+)
+
+// newTestSession is a session for an empty board, every item in the parsed markdown is new. Short IDs are stored
+// in a temporary MDELLO_HOME
+func newTestSession(t *testing.T) *BoardSession {
+	t.Helper()
+	t.Setenv(config.EnvHome, t.TempDir())
+
+	session, err := NewBoardSession(&trello.BoardSnapshot{Board: trello.Board{ID: "board", Name: "Board"}})
+	if err != nil {
+		t.Fatalf("NewBoardSession() failed: %v", err)
+	}
+	return session
+}
+
+func parseTestBoard(t *testing.T, content string) *ParsedBoard {
+	t.Helper()
+	board, err := FromMarkdown(strings.NewReader(content), newTestSession(t))
+	if err != nil {
+		t.Fatalf("FromMarkdown() failed: %v", err)
+	}
+	return board
+}
+
+func TestValidateBoardAcceptsAValidBoard(t *testing.T) {
+	board := parseTestBoard(t, `# Board
+@bug:red
+@front~end:sky_dark
+
+## Todo
+- [ ] Fix login @bug @front~end due:2025-07-25 09:30
+- [x] Ship it
+`)
+	if err := ValidateBoard(board, &config.Config{}); err != nil {
+		t.Errorf("ValidateBoard() = %v, want nil", err)
+	}
+}
+
+func TestValidateBoard(t *testing.T) {
+	board := parseTestBoard(t, `# Board
+@bug:red
+@bug:blue
+@idea:violet
+
+## Todo
+- [ ] Fix login @defect
+- [ ] Plan release due:2025-13-45 10:00
+
+## Todo
+- [ ] Write notes due:tomorrow
+`)
+
+	err := ValidateBoard(board, &config.Config{})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("ValidateBoard() = %v, want a *ValidationError", err)
+	}
+
+	want := []struct {
+		line    int
+		message string
+	}{
+		{3, "label 'bug' is defined more than once"},
+		{4, "'violet' is not a label colour"},
+		{7, "label 'defect' does not exist on the board"},
+		{8, "due date '2025-13-45 10:00' could not be read"},
+		{10, "list 'Todo' is defined more than once"},
+		{11, "due date could not be read"},
+	}
+	if len(validationErr.Diagnostics) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d:\n%v", len(validationErr.Diagnostics), len(want), err)
+	}
+	for i, diagnostic := range validationErr.Diagnostics {
+		if diagnostic.Line != want[i].line || diagnostic.Message != want[i].message {
+			t.Errorf("diagnostic %d = line %d: %s, want line %d: %s", i, diagnostic.Line, diagnostic.Message, want[i].line, want[i].message)
+		}
+	}
+}
+
+func TestValidateBoardUsesTheConfiguredDateFormat(t *testing.T) {
+	board := parseTestBoard(t, `# Board
+
+## Todo
+- [ ] Plan release due:25-07-2025 10:00
+`)
+
+	if err := ValidateBoard(board, &config.Config{}); err == nil {
+		t.Error("an EU date was accepted with the ISO default")
+	}
+
+	eu := &config.Config{Profile: config.Profile{DateFormat: config.DateFormatEU}}
+	if err := ValidateBoard(board, eu); err != nil {
+		t.Errorf("an EU date was rejected with the EU format: %v", err)
+	}
+}
+
+func TestDiagnosticsRoundTrip(t *testing.T) {
+	content := "# Board\n\n## Todo\n  - [ ] Fix login @defect\n"
+	diagnostics := []Diagnostic{{Line: 4, Message: "label 'defect' does not exist on the board", Hint: "Fix the spelling"}}
+
+	annotated := InsertDiagnostics(content, diagnostics)
+	want := "# Board\n\n## Todo\n  <!-- mdello: label 'defect' does not exist on the board. Fix the spelling -->\n  - [ ] Fix login @defect\n"
+	if annotated != want {
+		t.Errorf("InsertDiagnostics() =\n%s\nwant:\n%s", annotated, want)
+	}
+
+	if stripped := StripDiagnostics(annotated); stripped != content {
+		t.Errorf("StripDiagnostics() =\n%s\nwant:\n%s", stripped, content)
+	}
+}
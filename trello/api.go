@@ -0,0 +1,47 @@
+package trello
+
+// API is the set of trello operations the markdown layer depends on. TrelloClient implements it against the real
+// API, other implementations can wrap it to cache, record or dry-run requests
+type API interface {
+	// Boards
+	GetBoards() ([]Board, error)
+	GetBoard(boardID string) (*Board, error)
+	GetBoardSnapshot(boardID string) (*BoardSnapshot, error)
+	CreateBoard(params *CreateBoardParams) (*Board, error)
+	UpdateBoard(params *UpdateBoardParams) (*Board, error)
+	DeleteBoard(boardID string) error
+
+	// Labels
+	GetBoardLabels(boardID string) ([]Label, error)
+	CreateLabel(params *CreateLabelParams) (*Label, error)
+	UpdateLabel(params *UpdateLabelParams) (*Label, error)
+	DeleteLabel(labelID string) error
+
+	// Lists
+	GetLists(boardID string) ([]List, error)
+	GetList(listID string) (*List, error)
+	CreateList(params *CreateListParams) (*List, error)
+	UpdateList(params *UpdateListParams) (*List, error)
+	ArchiveList(params *ArchiveListParams) (*List, error)
+
+	// Cards
+	GetCards(listID string) ([]Card, error)
+	GetCard(cardID string) (*Card, error)
+	CreateCard(params *CreateCardParams) (*Card, error)
+	UpdateCard(params *UpdateCardParams) (*Card, error)
+	DeleteCard(cardID string) error
+	AddCardLabel(params *AddCardLabelParams) error
+	DeleteCardLabel(params *DeleteCardLabelParams) error
+
+	// Checklists
+	GetBoardChecklists(boardID string) ([]Checklist, error)
+	GetCardChecklists(cardID string) ([]Checklist, error)
+	CreateChecklist(params *CreateChecklistParams) (*Checklist, error)
+	UpdateChecklist(params *UpdateChecklistParams) (*Checklist, error)
+	DeleteChecklist(checklistID string) error
+	CreateCheckItem(params *CreateCheckItemParams) (*CheckItem, error)
+	UpdateCheckItem(params *UpdateCheckItemParams) (*CheckItem, error)
+	DeleteCheckItem(checklistID, checkItemID string) error
+}
+
+var _ API = (*TrelloClient)(nil)
@@ -2,31 +2,45 @@ package trello
 
 import (
 	"fmt"
-	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
 type TrelloClient struct {
-	apiKey     string
-	token      string
-	baseUrl    string
-	httpClient *http.Client
+	apiKey      string
+	token       string
+	baseUrl     string
+	httpClient  *http.Client
+	limiter     *tokenBucket
+	retryPolicy RetryPolicy
+	logf        func(format string, args ...any)
+	sleep       func(time.Duration)
 }
 
+const DefaultBaseURL = "https://api.trello.com/1"
+
 func NewTrelloClient(apiKey, token string) (*TrelloClient, error) {
+	return NewTrelloClientWithURL(DefaultBaseURL, apiKey, token)
+}
+
+// NewTrelloClientWithURL talks to another Trello compatible API, such as the fake server in trellotest
+func NewTrelloClientWithURL(baseURL, apiKey, token string) (*TrelloClient, error) {
 	trelloClient := TrelloClient{
 		token:   token,
 		apiKey:  apiKey,
-		baseUrl: "https://api.trello.com/1",
+		baseUrl: strings.TrimSuffix(baseURL, "/"),
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		limiter:     newTokenBucket(requestsPerWindow, rateLimitWindow),
+		retryPolicy: DefaultRetryPolicy,
+		logf:        func(format string, args ...any) {},
+		sleep:       time.Sleep,
 	}
 
-	err := trelloClient.HealthCheck()
-	if err != nil {
-		return nil, fmt.Errorf("Invalid Trello Token: %w", err)
+	if err := trelloClient.HealthCheck(); err != nil {
+		return nil, err
 	}
 
 	return &trelloClient, nil
@@ -36,19 +50,20 @@ func (t *TrelloClient) SetTimeout(timeout time.Duration) {
 	t.httpClient.Timeout = timeout
 }
 
-func (t TrelloClient) HealthCheck() error {
-	url := fmt.Sprintf("%s/members/me/?key=%s&token=%s", t.baseUrl, t.apiKey, t.token)
+func (t *TrelloClient) SetRetryPolicy(policy RetryPolicy) {
+	t.retryPolicy = policy
+}
 
-	r, e := t.httpClient.Get(url)
-	if e != nil {
-		log.Fatal(e)
-	}
-	defer r.Body.Close()
+// SetLogger receives verbose output such as retried requests
+func (t *TrelloClient) SetLogger(logf func(format string, args ...any)) {
+	t.logf = logf
+}
 
-	if r.StatusCode != 200 {
-		err := fmt.Sprintf("API unavailable: status %d", r.StatusCode)
-		log.Fatal(err)
+// HealthCheck makes sure trello can be reached with the token, the error matches ErrOffline, ErrTimeout,
+// ErrUnauthorized, ErrForbidden or ErrUnavailable for the common failures
+func (t *TrelloClient) HealthCheck() error {
+	if err := t.doRequest(http.MethodGet, "members/me/", nil, nil); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
 	}
-
 	return nil
 }
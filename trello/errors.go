@@ -1,11 +1,23 @@
 package trello
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 )
 
+// Failures callers handle the same way whichever request hit them, check for them with errors.Is
+var (
+	ErrOffline      = errors.New("could not reach trello")
+	ErrTimeout      = errors.New("trello did not respond in time")
+	ErrUnauthorized = errors.New("trello rejected the token")
+	ErrForbidden    = errors.New("the token does not have permission")
+	ErrUnavailable  = errors.New("trello is unavailable")
+)
+
 type Error struct {
 	StatusCode int    `json:"-"` // Don't try to unmarshal from JSON
 	Message    string `json:"message,omitempty"`
@@ -23,6 +35,19 @@ func (e Error) Error() string {
 	return fmt.Sprintf("Trello API Error %d: %s", e.StatusCode, getGenericErrorMessage(e.StatusCode))
 }
 
+// Is lets errors.Is match an API error against the failure its status code stands for
+func (e Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrUnavailable:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
 func (e Error) IsAuthError() bool {
 	return e.StatusCode == http.StatusUnauthorized
 }
@@ -39,6 +64,16 @@ func (e Error) IsBadRequestError() bool {
 	return e.StatusCode == http.StatusBadRequest
 }
 
+// networkError wraps an error from sending a request in ErrTimeout or ErrOffline, no response means trello was
+// never reached
+func networkError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("%w: %w", ErrOffline, err)
+}
+
 func handleHTTPResponse(response *http.Response) error {
 	if response.StatusCode >= 200 && response.StatusCode < 300 {
 		return nil
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func paramsToURLValues(params any) (url.Values, error) {
@@ -55,6 +56,16 @@ func paramsToURLValues(params any) (url.Values, error) {
 			strValue = strconv.FormatBool(field.Bool())
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			strValue = strconv.FormatInt(field.Int(), 10)
+		case reflect.Slice:
+			// Trello takes lists of IDs as a comma-separated string
+			items := make([]string, 0, field.Len())
+			for j := 0; j < field.Len(); j++ {
+				if field.Index(j).Kind() != reflect.String {
+					continue
+				}
+				items = append(items, field.Index(j).String())
+			}
+			strValue = strings.Join(items, ",")
 		default:
 			continue
 		}
@@ -65,7 +76,12 @@ func paramsToURLValues(params any) (url.Values, error) {
 	return values, nil
 }
 
+// doRequest sends a request and decodes the response into result, its errors never contain the key or token
 func (t *TrelloClient) doRequest(method, path string, queryParams url.Values, result any) error {
+	return t.redactError(t.sendRequest(method, path, queryParams, result))
+}
+
+func (t *TrelloClient) sendRequest(method, path string, queryParams url.Values, result any) error {
 	fullURL, err := url.JoinPath(t.baseUrl, path)
 	if err != nil {
 		return fmt.Errorf("failed to create URL path: %w", err)
@@ -76,10 +92,9 @@ func (t *TrelloClient) doRequest(method, path string, queryParams url.Values, re
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	query := req.URL.Query()
-	query.Set("key", t.apiKey)
-	query.Set("token", t.token)
+	req.Header.Set("Authorization", t.authorizationHeader())
 
+	query := req.URL.Query()
 	for key, vals := range queryParams {
 		for _, val := range vals {
 			query.Add(key, val)
@@ -87,9 +102,24 @@ func (t *TrelloClient) doRequest(method, path string, queryParams url.Values, re
 	}
 	req.URL.RawQuery = query.Encode()
 
-	response, err := t.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("network error: %w", err)
+	var response *http.Response
+	for attempt := 0; ; attempt++ {
+		t.limiter.wait()
+
+		response, err = t.httpClient.Do(req)
+		if err != nil {
+			return networkError(err)
+		}
+
+		if attempt >= t.retryPolicy.MaxRetries || !shouldRetry(method, response.StatusCode) {
+			break
+		}
+
+		delay := t.retryPolicy.retryDelay(attempt, response, time.Now())
+		response.Body.Close()
+		t.logRedacted("%s %s returned %d, retrying in %s (retry %d of %d)\n",
+			method, path, response.StatusCode, delay.Round(time.Millisecond), attempt+1, t.retryPolicy.MaxRetries)
+		t.sleep(delay)
 	}
 	defer response.Body.Close()
 
@@ -123,6 +153,28 @@ func (t *TrelloClient) GetBoard(boardID string) (*Board, error) {
 	return &board, nil
 }
 
+// GetBoardSnapshot fetches everything shown in the board markdown with the nested board resources
+func (t *TrelloClient) GetBoardSnapshot(boardID string) (*BoardSnapshot, error) {
+	if boardID == "" {
+		return nil, errors.New("boardID is required to get a board snapshot")
+	}
+
+	query := url.Values{}
+	query.Set("lists", "open")
+	query.Set("cards", "visible") // open would include open cards in archived lists, which the markdown cannot show
+	query.Set("labels", "all")
+	query.Set("labels_limit", "1000")
+	query.Set("checklists", "all")
+
+	var snapshot BoardSnapshot
+	path := fmt.Sprintf("/boards/%s", boardID)
+	err := t.doRequest("GET", path, query, &snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board snapshot with id %s: %w", boardID, err)
+	}
+	return &snapshot, nil
+}
+
 func (t *TrelloClient) GetBoardLabels(boardID string) ([]Label, error) {
 	var labels []Label
 
@@ -451,3 +503,143 @@ func (t *TrelloClient) DeleteCard(cardId string) error {
 
 	return nil
 }
+
+func (t *TrelloClient) GetBoardChecklists(boardID string) ([]Checklist, error) {
+	if boardID == "" {
+		return nil, errors.New("boardID is required to get checklists")
+	}
+
+	var checklists []Checklist
+
+	path := fmt.Sprintf("/boards/%s/checklists", boardID)
+	err := t.doRequest("GET", path, nil, &checklists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklists for board %s: %w", boardID, err)
+	}
+	return checklists, nil
+}
+
+func (t *TrelloClient) GetCardChecklists(cardID string) ([]Checklist, error) {
+	if cardID == "" {
+		return nil, errors.New("cardID is required to get checklists")
+	}
+
+	var checklists []Checklist
+
+	path := fmt.Sprintf("/cards/%s/checklists", cardID)
+	err := t.doRequest("GET", path, nil, &checklists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklists for card %s: %w", cardID, err)
+	}
+	return checklists, nil
+}
+
+func (t *TrelloClient) CreateChecklist(params *CreateChecklistParams) (*Checklist, error) {
+	if params == nil || params.IdCard == "" {
+		return nil, errors.New("CreateChecklistParams with a IdCard is required")
+	}
+
+	queryParams, err := paramsToURLValues(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not process create checklist params: %w", err)
+	}
+
+	var checklist Checklist
+
+	err = t.doRequest("POST", "/checklists", queryParams, &checklist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trello checklist: %w", err)
+	}
+	return &checklist, nil
+}
+
+func (t *TrelloClient) UpdateChecklist(params *UpdateChecklistParams) (*Checklist, error) {
+	if params == nil || params.ID == "" {
+		return nil, errors.New("UpdateChecklistParams with a valid ID is required")
+	}
+
+	queryParams, err := paramsToURLValues(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not process update checklist params: %w", err)
+	}
+
+	path := fmt.Sprintf("/checklists/%s", params.ID)
+
+	var checklist Checklist
+	err = t.doRequest("PUT", path, queryParams, &checklist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update trello checklist: %w", err)
+	}
+
+	return &checklist, nil
+}
+
+func (t *TrelloClient) DeleteChecklist(checklistID string) error {
+	if checklistID == "" {
+		return errors.New("checklistID is required to delete a trello checklist")
+	}
+
+	path := fmt.Sprintf("/checklists/%s", checklistID)
+	err := t.doRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete trello checklist %s: %w", checklistID, err)
+	}
+
+	return nil
+}
+
+func (t *TrelloClient) CreateCheckItem(params *CreateCheckItemParams) (*CheckItem, error) {
+	if params == nil || params.ID == "" || params.Name == "" {
+		return nil, errors.New("CreateCheckItemParams requires a checklist ID and Name")
+	}
+
+	queryParams, err := paramsToURLValues(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not process create check item params: %w", err)
+	}
+
+	path := fmt.Sprintf("/checklists/%s/checkItems", params.ID)
+
+	var checkItem CheckItem
+	err = t.doRequest("POST", path, queryParams, &checkItem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trello check item: %w", err)
+	}
+
+	return &checkItem, nil
+}
+
+func (t *TrelloClient) UpdateCheckItem(params *UpdateCheckItemParams) (*CheckItem, error) {
+	if params == nil || params.ID == "" || params.CardID == "" {
+		return nil, errors.New("UpdateCheckItemParams requires valid ID and CardID")
+	}
+
+	queryParams, err := paramsToURLValues(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not process update check item params: %w", err)
+	}
+
+	path := fmt.Sprintf("/cards/%s/checkItem/%s", params.CardID, params.ID)
+
+	var checkItem CheckItem
+	err = t.doRequest("PUT", path, queryParams, &checkItem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update trello check item: %w", err)
+	}
+
+	return &checkItem, nil
+}
+
+func (t *TrelloClient) DeleteCheckItem(checklistID, checkItemID string) error {
+	if checklistID == "" || checkItemID == "" {
+		return errors.New("checklistID and checkItemID are required to delete a trello check item")
+	}
+
+	path := fmt.Sprintf("/checklists/%s/checkItems/%s", checklistID, checkItemID)
+	err := t.doRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete trello check item %s: %w", checkItemID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package trello
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// Credentials are sent in a header rather than the URL, but anything the client returns or logs still goes
+// through redact in case a secret shows up in a message from the network stack or trello
+
+// redact replaces the client's key and token in s
+func (t *TrelloClient) redact(s string) string {
+	for _, secret := range []string{t.token, t.apiKey} {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
+
+// redactError hides the key and token in err's message, errors.Is and errors.As still see the wrapped errors
+func (t *TrelloClient) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var alreadyRedacted *redactedError
+	if errors.As(err, &alreadyRedacted) {
+		return err
+	}
+	return &redactedError{err: err, message: t.redact(err.Error())}
+}
+
+func (t *TrelloClient) logRedacted(format string, args ...any) {
+	t.logf("%s", t.redact(fmt.Sprintf(format, args...)))
+}
+
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// authorizationHeader is the OAuth header trello accepts in place of the key and token query parameters
+func (t *TrelloClient) authorizationHeader() string {
+	return fmt.Sprintf(`OAuth oauth_consumer_key="%s", oauth_token="%s"`, t.apiKey, t.token)
+}
@@ -0,0 +1,110 @@
+package trello
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Trello allows 100 requests per 10 second window for each token. Requests are throttled client side so a large
+// edit does not run into the limit, and responses that still come back 429 or 5xx are retried with backoff
+
+const (
+	requestsPerWindow = 100
+	rateLimitWindow   = 10 * time.Second
+)
+
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 5,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// tokenBucket holds up to capacity requests and refills at the rate trello allows them
+type tokenBucket struct {
+	mu         sync.Mutex
+	tokens     float64
+	capacity   float64
+	refillRate float64 // Tokens per second
+	lastRefill time.Time
+	now        func() time.Time
+	sleep      func(time.Duration)
+}
+
+func newTokenBucket(capacity int, window time.Duration) *tokenBucket {
+	return &tokenBucket{
+		tokens:     float64(capacity),
+		capacity:   float64(capacity),
+		refillRate: float64(capacity) / window.Seconds(),
+		lastRefill: time.Now(),
+		now:        time.Now,
+		sleep:      time.Sleep,
+	}
+}
+
+// wait blocks until a request may be sent
+func (b *tokenBucket) wait() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		now := b.now()
+		b.tokens = min(b.capacity, b.tokens+now.Sub(b.lastRefill).Seconds()*b.refillRate)
+		b.lastRefill = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			return
+		}
+
+		missing := 1 - b.tokens
+		b.sleep(time.Duration(missing / b.refillRate * float64(time.Second)))
+	}
+}
+
+// shouldRetry reports whether a failed response is worth another attempt. 429 responses were never processed so
+// they are always retried, server errors only for methods that are safe to repeat so a create is not duplicated
+func shouldRetry(method string, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if statusCode < 500 {
+		return false
+	}
+	return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+}
+
+// retryDelay honours Retry-After when trello sends it, otherwise it backs off exponentially with full jitter
+func (p RetryPolicy) retryDelay(attempt int, response *http.Response, now time.Time) time.Duration {
+	if retryAfter := parseRetryAfter(response.Header.Get("Retry-After"), now); retryAfter > 0 {
+		return min(retryAfter, p.MaxDelay)
+	}
+
+	backoff := p.BaseDelay << attempt
+	if backoff <= 0 || backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// Retry-After is either a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if retryAt, err := http.ParseTime(value); err == nil {
+		return retryAt.Sub(now)
+	}
+	return 0
+}
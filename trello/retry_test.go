@@ -0,0 +1,200 @@
+package trello
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient skips the health check and records the delays instead of sleeping
+func newTestClient(t *testing.T, baseURL string) (*TrelloClient, *[]time.Duration) {
+	t.Helper()
+
+	var delays []time.Duration
+	client := &TrelloClient{
+		apiKey:      "test-key",
+		token:       "test-token",
+		baseUrl:     baseURL,
+		httpClient:  &http.Client{Timeout: 5 * time.Second},
+		limiter:     newTokenBucket(requestsPerWindow, rateLimitWindow),
+		retryPolicy: DefaultRetryPolicy,
+		logf:        func(format string, args ...any) {},
+		sleep:       func(delay time.Duration) { delays = append(delays, delay) },
+	}
+	return client, &delays
+}
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		method     string
+		statusCode int
+		want       bool
+	}{
+		{http.MethodPost, http.StatusTooManyRequests, true},
+		{http.MethodGet, http.StatusTooManyRequests, true},
+		{http.MethodGet, http.StatusServiceUnavailable, true},
+		{http.MethodPut, http.StatusBadGateway, true},
+		{http.MethodDelete, http.StatusInternalServerError, true},
+		{http.MethodPost, http.StatusServiceUnavailable, false}, // A retried create could be duplicated
+		{http.MethodGet, http.StatusBadRequest, false},
+		{http.MethodGet, http.StatusNotFound, false},
+	}
+
+	for _, test := range tests {
+		if got := shouldRetry(test.method, test.statusCode); got != test.want {
+			t.Errorf("shouldRetry(%s, %d) = %t, want %t", test.method, test.statusCode, got, test.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 7, 25, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"soon", 0},
+	}
+
+	for _, test := range tests {
+		if got := parseRetryAfter(test.value, now); got != test.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second}
+	now := time.Now()
+
+	withRetryAfter := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+	}
+
+	if got := policy.retryDelay(0, withRetryAfter("2"), now); got != 2*time.Second {
+		t.Errorf("Retry-After 2: got %s, want 2s", got)
+	}
+	if got := policy.retryDelay(0, withRetryAfter("60"), now); got != policy.MaxDelay {
+		t.Errorf("Retry-After 60: got %s, want it capped at %s", got, policy.MaxDelay)
+	}
+
+	// Without Retry-After the delay is jittered between 0 and the exponential backoff
+	for attempt := range 10 {
+		backoff := min(policy.BaseDelay<<attempt, policy.MaxDelay)
+		for range 20 {
+			if got := policy.retryDelay(attempt, withRetryAfter(""), now); got < 0 || got > backoff {
+				t.Fatalf("attempt %d: delay %s outside [0, %s]", attempt, got, backoff)
+			}
+		}
+	}
+}
+
+func TestTokenBucketWaitsForRefill(t *testing.T) {
+	now := time.Date(2025, 7, 25, 12, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+
+	bucket := newTokenBucket(10, 10*time.Second) // One token a second
+	bucket.lastRefill = now
+	bucket.now = func() time.Time { return now }
+	bucket.sleep = func(delay time.Duration) {
+		slept = append(slept, delay)
+		now = now.Add(delay)
+	}
+
+	for range 10 {
+		bucket.wait()
+	}
+	if len(slept) != 0 {
+		t.Fatalf("a full bucket slept %v", slept)
+	}
+
+	bucket.wait()
+	if len(slept) != 1 || slept[0] != time.Second {
+		t.Fatalf("an empty bucket slept %v, want [1s]", slept)
+	}
+
+	// Refills stop at the capacity
+	now = now.Add(time.Hour)
+	for range 10 {
+		bucket.wait()
+	}
+	if len(slept) != 1 {
+		t.Fatalf("a refilled bucket slept %v", slept[1:])
+	}
+}
+
+func TestRequestRetriesHonourRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"id": "board"}`))
+	}))
+	defer server.Close()
+
+	client, delays := newTestClient(t, server.URL)
+	var board Board
+	if err := client.doRequest(http.MethodPost, "boards", nil, &board); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if board.ID != "board" {
+		t.Errorf("decoded board ID %q, want board", board.ID)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("sent %d requests, want 3", got)
+	}
+	if len(*delays) != 2 || (*delays)[0] != 2*time.Second || (*delays)[1] != 2*time.Second {
+		t.Errorf("waited %v between attempts, want [2s 2s]", *delays)
+	}
+}
+
+func TestRequestRetriesStopAtMaxRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, delays := newTestClient(t, server.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	err := client.doRequest(http.MethodGet, "boards/1", nil, nil)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got %v, want ErrUnavailable", err)
+	}
+	if got := requests.Load(); got != 4 {
+		t.Errorf("sent %d requests, want the first attempt and 3 retries", got)
+	}
+	if len(*delays) != 3 {
+		t.Errorf("waited %d times, want 3", len(*delays))
+	}
+}
+
+func TestRequestDoesNotRetryCreatesOnServerErrors(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, _ := newTestClient(t, server.URL)
+	if err := client.doRequest(http.MethodPost, "cards", nil, nil); err == nil {
+		t.Fatal("a 502 response did not return an error")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+}
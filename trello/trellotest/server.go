@@ -0,0 +1,768 @@
+// Package trellotest runs an in-memory fake of the parts of the Trello API mdello uses, so the whole
+// pull, edit, diff and apply cycle can run without a network:
+//
+//	server := trellotest.NewServer()
+//	defer server.Close()
+//	board := server.AddBoard("Project")
+//	server.AddCard(server.AddList(board.ID, "To Do").ID, "Write tests")
+//	client, err := server.Client()
+package trellotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vinzmyko/mdello/trello"
+)
+
+const positionGap = 65536.0 // Spacing trello uses between positions
+
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	nextID     int
+	boards     map[string]*trello.Board
+	labels     map[string]*trello.Label
+	lists      map[string]*trello.List
+	cards      map[string]*trello.Card
+	checklists map[string]*trello.Checklist
+
+	failures   int // Requests still to fail with failStatus
+	failStatus int
+}
+
+func NewServer() *Server {
+	s := &Server{
+		boards:     make(map[string]*trello.Board),
+		labels:     make(map[string]*trello.Label),
+		lists:      make(map[string]*trello.List),
+		cards:      make(map[string]*trello.Card),
+		checklists: make(map[string]*trello.Checklist),
+	}
+
+	mux := http.NewServeMux()
+	s.routes(mux)
+	s.Server = httptest.NewServer(s.middleware(mux))
+	return s
+}
+
+// Client returns a client for the fake server, any key and token are accepted
+func (s *Server) Client() (*trello.TrelloClient, error) {
+	return trello.NewTrelloClientWithURL(s.URL, "test-key", "test-token")
+}
+
+// FailNext makes the next count requests fail with statusCode, e.g. to exercise retries
+func (s *Server) FailNext(count, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = count
+	s.failStatus = statusCode
+}
+
+func (s *Server) AddBoard(name string) trello.Board {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.createBoard(name)
+}
+
+func (s *Server) AddLabel(boardID, name, colour string) trello.Label {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.createLabel(boardID, name, colour)
+}
+
+func (s *Server) AddList(boardID, name string) trello.List {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.createList(boardID, name, s.bottomPos(s.listPositions(boardID, "")))
+}
+
+func (s *Server) AddCard(listID, name string) trello.Card {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.renderCard(s.createCard(listID, name, s.bottomPos(s.cardPositions(listID, ""))))
+}
+
+func (s *Server) AddChecklist(cardID, name string) trello.Checklist {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.createChecklist(cardID, name)
+}
+
+func (s *Server) AddCheckItem(checklistID, name string, complete bool) trello.CheckItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createCheckItem(s.checklists[checklistID], name, complete)
+}
+
+// Snapshot returns the board as GetBoardSnapshot would, for checking the state after applying changes
+func (s *Server) Snapshot(boardID string) *trello.BoardSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot(boardID, "visible")
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !hasCredentials(r) {
+			writeError(w, http.StatusUnauthorized, "invalid key")
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.failures > 0 {
+			s.failures--
+			writeError(w, s.failStatus, http.StatusText(s.failStatus))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Handlers run with mu held by the middleware
+func (s *Server) routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /members/me/{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"id": "member", "username": "trellotest"})
+	})
+	mux.HandleFunc("GET /members/me/boards", func(w http.ResponseWriter, r *http.Request) {
+		boards := []trello.Board{}
+		for _, board := range s.boards {
+			if !board.Closed {
+				boards = append(boards, s.renderBoard(board))
+			}
+		}
+		sort.Slice(boards, func(i, j int) bool { return boards[i].ID < boards[j].ID })
+		writeJSON(w, boards)
+	})
+
+	// Boards
+	mux.HandleFunc("POST /boards", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.renderBoard(s.createBoard(r.FormValue("name"))))
+	})
+	mux.HandleFunc("GET /boards/{id}", s.withBoard(func(w http.ResponseWriter, r *http.Request, board *trello.Board) {
+		writeJSON(w, s.snapshot(board.ID, r.URL.Query().Get("cards")))
+	}))
+	mux.HandleFunc("PUT /boards/{id}", s.withBoard(func(w http.ResponseWriter, r *http.Request, board *trello.Board) {
+		setString(r, "name", &board.Name)
+		setString(r, "desc", &board.Desc)
+		setBool(r, "closed", &board.Closed)
+		setBool(r, "subscribed", &board.Subscribed)
+		writeJSON(w, s.renderBoard(board))
+	}))
+	mux.HandleFunc("DELETE /boards/{id}", s.withBoard(func(w http.ResponseWriter, r *http.Request, board *trello.Board) {
+		delete(s.boards, board.ID)
+		writeJSON(w, map[string]any{})
+	}))
+	mux.HandleFunc("GET /boards/{id}/labels", s.withBoard(func(w http.ResponseWriter, r *http.Request, board *trello.Board) {
+		writeJSON(w, s.boardLabels(board.ID))
+	}))
+	mux.HandleFunc("GET /boards/{id}/lists", s.withBoard(func(w http.ResponseWriter, r *http.Request, board *trello.Board) {
+		writeJSON(w, s.boardLists(board.ID))
+	}))
+	mux.HandleFunc("GET /boards/{id}/checklists", s.withBoard(func(w http.ResponseWriter, r *http.Request, board *trello.Board) {
+		writeJSON(w, s.boardChecklists(board.ID))
+	}))
+
+	// Labels
+	mux.HandleFunc("POST /labels", func(w http.ResponseWriter, r *http.Request) {
+		if s.boards[r.FormValue("idBoard")] == nil {
+			writeError(w, http.StatusBadRequest, "invalid value for idBoard")
+			return
+		}
+		writeJSON(w, s.createLabel(r.FormValue("idBoard"), r.FormValue("name"), r.FormValue("color")))
+	})
+	mux.HandleFunc("PUT /labels/{id}", s.withLabel(func(w http.ResponseWriter, r *http.Request, label *trello.Label) {
+		setString(r, "name", &label.Name)
+		setString(r, "color", &label.Colour)
+		writeJSON(w, label)
+	}))
+	mux.HandleFunc("DELETE /labels/{id}", s.withLabel(func(w http.ResponseWriter, r *http.Request, label *trello.Label) {
+		delete(s.labels, label.ID)
+		for _, card := range s.cards {
+			card.IdLabels = without(card.IdLabels, label.ID)
+		}
+		writeJSON(w, map[string]any{})
+	}))
+
+	// Lists
+	mux.HandleFunc("POST /lists", func(w http.ResponseWriter, r *http.Request) {
+		boardID := r.FormValue("idBoard")
+		if s.boards[boardID] == nil {
+			writeError(w, http.StatusBadRequest, "invalid value for idBoard")
+			return
+		}
+		pos, err := resolvePos(r.FormValue("pos"), s.listPositions(boardID, ""))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, s.createList(boardID, r.FormValue("name"), pos))
+	})
+	mux.HandleFunc("GET /lists/{id}", s.withList(func(w http.ResponseWriter, r *http.Request, list *trello.List) {
+		writeJSON(w, list)
+	}))
+	mux.HandleFunc("PUT /lists/{id}", s.withList(func(w http.ResponseWriter, r *http.Request, list *trello.List) {
+		if r.Form.Has("pos") {
+			pos, err := resolvePos(r.FormValue("pos"), s.listPositions(list.IdBoard, list.ID))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			list.Pos = pos
+		}
+		setString(r, "name", &list.Name)
+		setBool(r, "closed", &list.Closed)
+		setBool(r, "subscribed", &list.Subscribed)
+		writeJSON(w, list)
+	}))
+	mux.HandleFunc("PUT /lists/{id}/closed", s.withList(func(w http.ResponseWriter, r *http.Request, list *trello.List) {
+		setBool(r, "value", &list.Closed)
+		writeJSON(w, list)
+	}))
+	mux.HandleFunc("GET /lists/{id}/cards", s.withList(func(w http.ResponseWriter, r *http.Request, list *trello.List) {
+		writeJSON(w, s.listCards(list.ID))
+	}))
+
+	// Cards
+	mux.HandleFunc("POST /cards", func(w http.ResponseWriter, r *http.Request) {
+		listID := r.FormValue("idList")
+		if s.lists[listID] == nil {
+			writeError(w, http.StatusBadRequest, "invalid value for idList")
+			return
+		}
+		pos, err := resolvePos(r.FormValue("pos"), s.cardPositions(listID, ""))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		card := s.createCard(listID, r.FormValue("name"), pos)
+		if status, err := s.updateCard(r, card); err != nil {
+			delete(s.cards, card.ID)
+			writeError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, s.renderCard(card))
+	})
+	mux.HandleFunc("GET /cards/{id}", s.withCard(func(w http.ResponseWriter, r *http.Request, card *trello.Card) {
+		writeJSON(w, s.renderCard(card))
+	}))
+	mux.HandleFunc("PUT /cards/{id}", s.withCard(func(w http.ResponseWriter, r *http.Request, card *trello.Card) {
+		if status, err := s.updateCard(r, card); err != nil {
+			writeError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, s.renderCard(card))
+	}))
+	mux.HandleFunc("DELETE /cards/{id}", s.withCard(func(w http.ResponseWriter, r *http.Request, card *trello.Card) {
+		delete(s.cards, card.ID)
+		for id, checklist := range s.checklists {
+			if checklist.IdCard == card.ID {
+				delete(s.checklists, id)
+			}
+		}
+		writeJSON(w, map[string]any{})
+	}))
+	mux.HandleFunc("POST /cards/{id}/idLabels", s.withCard(func(w http.ResponseWriter, r *http.Request, card *trello.Card) {
+		labelID := r.FormValue("value")
+		if s.labels[labelID] == nil {
+			writeError(w, http.StatusBadRequest, "invalid value for value")
+			return
+		}
+		if contains(card.IdLabels, labelID) {
+			writeError(w, http.StatusBadRequest, "that label is already on the card")
+			return
+		}
+		card.IdLabels = append(card.IdLabels, labelID)
+		writeJSON(w, card.IdLabels)
+	}))
+	mux.HandleFunc("DELETE /cards/{id}/idLabels/{labelID}", s.withCard(func(w http.ResponseWriter, r *http.Request, card *trello.Card) {
+		labelID := r.PathValue("labelID")
+		if !contains(card.IdLabels, labelID) {
+			writeError(w, http.StatusNotFound, "that label is not on the card")
+			return
+		}
+		card.IdLabels = without(card.IdLabels, labelID)
+		writeJSON(w, card.IdLabels)
+	}))
+	mux.HandleFunc("GET /cards/{id}/checklists", s.withCard(func(w http.ResponseWriter, r *http.Request, card *trello.Card) {
+		writeJSON(w, s.cardChecklists(card.ID))
+	}))
+	mux.HandleFunc("PUT /cards/{id}/checkItem/{checkItemID}", s.withCard(func(w http.ResponseWriter, r *http.Request, card *trello.Card) {
+		checklist, i := s.findCheckItem(card.ID, r.PathValue("checkItemID"))
+		if checklist == nil {
+			writeError(w, http.StatusNotFound, "check item not found")
+			return
+		}
+		checkItem := &checklist.CheckItems[i]
+		setString(r, "name", &checkItem.Name)
+		setString(r, "state", &checkItem.State)
+		if r.Form.Has("pos") {
+			pos, err := resolvePos(r.FormValue("pos"), checkItemPositions(checklist, checkItem.ID))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			checkItem.Pos = pos
+		}
+		writeJSON(w, checkItem)
+	}))
+
+	// Checklists
+	mux.HandleFunc("POST /checklists", func(w http.ResponseWriter, r *http.Request) {
+		if s.cards[r.FormValue("idCard")] == nil {
+			writeError(w, http.StatusBadRequest, "invalid value for idCard")
+			return
+		}
+		writeJSON(w, s.createChecklist(r.FormValue("idCard"), r.FormValue("name")))
+	})
+	mux.HandleFunc("PUT /checklists/{id}", s.withChecklist(func(w http.ResponseWriter, r *http.Request, checklist *trello.Checklist) {
+		setString(r, "name", &checklist.Name)
+		writeJSON(w, checklist)
+	}))
+	mux.HandleFunc("DELETE /checklists/{id}", s.withChecklist(func(w http.ResponseWriter, r *http.Request, checklist *trello.Checklist) {
+		delete(s.checklists, checklist.ID)
+		writeJSON(w, map[string]any{})
+	}))
+	mux.HandleFunc("POST /checklists/{id}/checkItems", s.withChecklist(func(w http.ResponseWriter, r *http.Request, checklist *trello.Checklist) {
+		writeJSON(w, s.createCheckItem(checklist, r.FormValue("name"), r.FormValue("checked") == "true"))
+	}))
+	mux.HandleFunc("DELETE /checklists/{id}/checkItems/{checkItemID}", s.withChecklist(func(w http.ResponseWriter, r *http.Request, checklist *trello.Checklist) {
+		for i, checkItem := range checklist.CheckItems {
+			if checkItem.ID == r.PathValue("checkItemID") {
+				checklist.CheckItems = append(checklist.CheckItems[:i], checklist.CheckItems[i+1:]...)
+				writeJSON(w, map[string]any{})
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "check item not found")
+	}))
+}
+
+// updateCard applies the card fields of a create or update request
+func (s *Server) updateCard(r *http.Request, card *trello.Card) (int, error) {
+	if r.Form.Has("idList") {
+		listID := r.FormValue("idList")
+		if s.lists[listID] == nil {
+			return http.StatusBadRequest, fmt.Errorf("invalid value for idList")
+		}
+		card.IdList = listID
+	}
+	if r.Form.Has("pos") && r.Method == http.MethodPut {
+		pos, err := resolvePos(r.FormValue("pos"), s.cardPositions(card.IdList, card.ID))
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+		card.Pos = pos
+	}
+	if r.Form.Has("idLabels") {
+		card.IdLabels = []string{}
+		for _, labelID := range strings.Split(r.FormValue("idLabels"), ",") {
+			if labelID == "" {
+				continue
+			}
+			if s.labels[labelID] == nil {
+				return http.StatusBadRequest, fmt.Errorf("invalid value for idLabels")
+			}
+			card.IdLabels = append(card.IdLabels, labelID)
+		}
+	}
+	if r.Form.Has("due") {
+		if due := r.FormValue("due"); due == "" {
+			card.Due = nil
+		} else {
+			card.Due = &due
+		}
+	}
+	setString(r, "name", &card.Name)
+	setString(r, "desc", &card.Desc)
+	setBool(r, "closed", &card.Closed)
+	setBool(r, "dueComplete", &card.Badges.DueComplete)
+	setBool(r, "subscribed", &card.Subscribed)
+	return 0, nil
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%024x", s.nextID)
+}
+
+func (s *Server) createBoard(name string) *trello.Board {
+	board := &trello.Board{ID: s.newID(), Name: name}
+	s.boards[board.ID] = board
+	return board
+}
+
+func (s *Server) createLabel(boardID, name, colour string) *trello.Label {
+	label := &trello.Label{ID: s.newID(), IDBoard: boardID, Name: name, Colour: colour}
+	s.labels[label.ID] = label
+	return label
+}
+
+func (s *Server) createList(boardID, name string, pos float64) *trello.List {
+	list := &trello.List{ID: s.newID(), IdBoard: boardID, Name: name, Pos: pos}
+	s.lists[list.ID] = list
+	return list
+}
+
+func (s *Server) createCard(listID, name string, pos float64) *trello.Card {
+	card := &trello.Card{
+		ID:       s.newID(),
+		IdBoard:  s.lists[listID].IdBoard,
+		IdList:   listID,
+		Name:     name,
+		Pos:      pos,
+		IdLabels: []string{},
+	}
+	s.cards[card.ID] = card
+	return card
+}
+
+func (s *Server) createChecklist(cardID, name string) *trello.Checklist {
+	var positions []float64
+	for _, checklist := range s.cardChecklists(cardID) {
+		positions = append(positions, checklist.Pos)
+	}
+
+	checklist := &trello.Checklist{
+		ID:         s.newID(),
+		IdBoard:    s.cards[cardID].IdBoard,
+		IdCard:     cardID,
+		Name:       name,
+		Pos:        s.bottomPos(positions),
+		CheckItems: []trello.CheckItem{},
+	}
+	s.checklists[checklist.ID] = checklist
+	return checklist
+}
+
+func (s *Server) createCheckItem(checklist *trello.Checklist, name string, complete bool) trello.CheckItem {
+	state := "incomplete"
+	if complete {
+		state = "complete"
+	}
+
+	checkItem := trello.CheckItem{
+		ID:          s.newID(),
+		IdChecklist: checklist.ID,
+		Name:        name,
+		State:       state,
+		Pos:         s.bottomPos(checkItemPositions(checklist, "")),
+	}
+	checklist.CheckItems = append(checklist.CheckItems, checkItem)
+	return checkItem
+}
+
+// snapshot returns the board with its open lists. Like trello, the cards filter open includes open cards in
+// archived lists and visible leaves them out
+func (s *Server) snapshot(boardID, cardsFilter string) *trello.BoardSnapshot {
+	snapshot := &trello.BoardSnapshot{
+		Board:      s.renderBoard(s.boards[boardID]),
+		Lists:      s.boardLists(boardID),
+		Cards:      []trello.Card{},
+		Checklists: s.boardChecklists(boardID),
+	}
+	for _, card := range s.cards {
+		if card.IdBoard != boardID || card.Closed {
+			continue
+		}
+		if list := s.lists[card.IdList]; cardsFilter == "visible" && list != nil && list.Closed {
+			continue
+		}
+		snapshot.Cards = append(snapshot.Cards, s.renderCard(card))
+	}
+	sortByPos(snapshot.Cards, func(card trello.Card) float64 { return card.Pos })
+	return snapshot
+}
+
+func (s *Server) renderBoard(board *trello.Board) trello.Board {
+	rendered := *board
+	rendered.Labels = s.boardLabels(board.ID)
+	return rendered
+}
+
+// renderCard fills in the card's labels, so renamed labels show up on every card
+func (s *Server) renderCard(card *trello.Card) trello.Card {
+	rendered := *card
+	rendered.Labels = []trello.CardLabel{}
+	for _, labelID := range card.IdLabels {
+		label := s.labels[labelID]
+		rendered.Labels = append(rendered.Labels, trello.CardLabel{ID: label.ID, IdBoard: label.IDBoard, Name: label.Name, Color: label.Colour})
+	}
+	return rendered
+}
+
+func (s *Server) boardLabels(boardID string) []trello.Label {
+	labels := []trello.Label{}
+	for _, label := range s.labels {
+		if label.IDBoard == boardID {
+			labels = append(labels, *label)
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].ID < labels[j].ID })
+	return labels
+}
+
+func (s *Server) boardLists(boardID string) []trello.List {
+	lists := []trello.List{}
+	for _, list := range s.lists {
+		if list.IdBoard == boardID && !list.Closed {
+			lists = append(lists, *list)
+		}
+	}
+	sortByPos(lists, func(list trello.List) float64 { return list.Pos })
+	return lists
+}
+
+func (s *Server) listCards(listID string) []trello.Card {
+	cards := []trello.Card{}
+	for _, card := range s.cards {
+		if card.IdList == listID && !card.Closed {
+			cards = append(cards, s.renderCard(card))
+		}
+	}
+	sortByPos(cards, func(card trello.Card) float64 { return card.Pos })
+	return cards
+}
+
+func (s *Server) boardChecklists(boardID string) []trello.Checklist {
+	checklists := []trello.Checklist{}
+	for _, checklist := range s.checklists {
+		if checklist.IdBoard == boardID {
+			checklists = append(checklists, *checklist)
+		}
+	}
+	sortByPos(checklists, func(checklist trello.Checklist) float64 { return checklist.Pos })
+	return checklists
+}
+
+func (s *Server) cardChecklists(cardID string) []trello.Checklist {
+	checklists := []trello.Checklist{}
+	for _, checklist := range s.checklists {
+		if checklist.IdCard == cardID {
+			checklists = append(checklists, *checklist)
+		}
+	}
+	sortByPos(checklists, func(checklist trello.Checklist) float64 { return checklist.Pos })
+	return checklists
+}
+
+func (s *Server) findCheckItem(cardID, checkItemID string) (*trello.Checklist, int) {
+	for _, checklist := range s.checklists {
+		if checklist.IdCard != cardID {
+			continue
+		}
+		for i, checkItem := range checklist.CheckItems {
+			if checkItem.ID == checkItemID {
+				return checklist, i
+			}
+		}
+	}
+	return nil, 0
+}
+
+// Positions of the other open lists on the board, skipping the list being moved
+func (s *Server) listPositions(boardID, skipID string) []float64 {
+	var positions []float64
+	for _, list := range s.lists {
+		if list.IdBoard == boardID && !list.Closed && list.ID != skipID {
+			positions = append(positions, list.Pos)
+		}
+	}
+	return positions
+}
+
+func (s *Server) cardPositions(listID, skipID string) []float64 {
+	var positions []float64
+	for _, card := range s.cards {
+		if card.IdList == listID && !card.Closed && card.ID != skipID {
+			positions = append(positions, card.Pos)
+		}
+	}
+	return positions
+}
+
+func checkItemPositions(checklist *trello.Checklist, skipID string) []float64 {
+	var positions []float64
+	for _, checkItem := range checklist.CheckItems {
+		if checkItem.ID != skipID {
+			positions = append(positions, checkItem.Pos)
+		}
+	}
+	return positions
+}
+
+func (s *Server) bottomPos(positions []float64) float64 {
+	pos, _ := resolvePos("bottom", positions)
+	return pos
+}
+
+// resolvePos turns a pos parameter into a position the way trello does: top goes above the first item, bottom
+// (the default) below the last one and a number is used as it is
+func resolvePos(value string, positions []float64) (float64, error) {
+	switch value {
+	case "top":
+		if len(positions) == 0 {
+			return positionGap, nil
+		}
+		return minOf(positions) / 2, nil
+	case "", "bottom":
+		if len(positions) == 0 {
+			return positionGap, nil
+		}
+		return maxOf(positions) + positionGap, nil
+	}
+
+	pos, err := strconv.ParseFloat(value, 64)
+	if err != nil || pos <= 0 {
+		return 0, fmt.Errorf("invalid value for pos")
+	}
+	return pos, nil
+}
+
+func (s *Server) withBoard(handler func(http.ResponseWriter, *http.Request, *trello.Board)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		board := s.boards[r.PathValue("id")]
+		if board == nil {
+			writeError(w, http.StatusNotFound, "board not found")
+			return
+		}
+		handler(w, r, board)
+	}
+}
+
+func (s *Server) withLabel(handler func(http.ResponseWriter, *http.Request, *trello.Label)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		label := s.labels[r.PathValue("id")]
+		if label == nil {
+			writeError(w, http.StatusNotFound, "label not found")
+			return
+		}
+		handler(w, r, label)
+	}
+}
+
+func (s *Server) withList(handler func(http.ResponseWriter, *http.Request, *trello.List)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list := s.lists[r.PathValue("id")]
+		if list == nil {
+			writeError(w, http.StatusNotFound, "list not found")
+			return
+		}
+		handler(w, r, list)
+	}
+}
+
+func (s *Server) withCard(handler func(http.ResponseWriter, *http.Request, *trello.Card)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		card := s.cards[r.PathValue("id")]
+		if card == nil {
+			writeError(w, http.StatusNotFound, "card not found")
+			return
+		}
+		handler(w, r, card)
+	}
+}
+
+func (s *Server) withChecklist(handler func(http.ResponseWriter, *http.Request, *trello.Checklist)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		checklist := s.checklists[r.PathValue("id")]
+		if checklist == nil {
+			writeError(w, http.StatusNotFound, "checklist not found")
+			return
+		}
+		handler(w, r, checklist)
+	}
+}
+
+// hasCredentials accepts the key and token in an OAuth header or, like trello, as query parameters
+func hasCredentials(r *http.Request) bool {
+	query := r.URL.Query()
+	if query.Get("key") != "" && query.Get("token") != "" {
+		return true
+	}
+
+	header, found := strings.CutPrefix(r.Header.Get("Authorization"), "OAuth ")
+	if !found {
+		return false
+	}
+	params := make(map[string]string)
+	for _, param := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		params[name] = strings.Trim(value, `"`)
+	}
+	return params["oauth_consumer_key"] != "" && params["oauth_token"] != ""
+}
+
+func setString(r *http.Request, key string, field *string) {
+	if r.Form.Has(key) {
+		*field = r.FormValue(key)
+	}
+}
+
+func setBool(r *http.Request, key string, field *bool) {
+	if r.Form.Has(key) {
+		*field = r.FormValue(key) == "true"
+	}
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(trello.Error{Message: message})
+}
+
+func sortByPos[T any](items []T, pos func(T) float64) {
+	sort.SliceStable(items, func(i, j int) bool { return pos(items[i]) < pos(items[j]) })
+}
+
+func contains(ids []string, id string) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
+
+func without(ids []string, id string) []string {
+	kept := []string{}
+	for _, existing := range ids {
+		if existing != id {
+			kept = append(kept, existing)
+		}
+	}
+	return kept
+}
+
+func minOf(values []float64) float64 {
+	result := values[0]
+	for _, value := range values[1:] {
+		result = min(result, value)
+	}
+	return result
+}
+
+func maxOf(values []float64) float64 {
+	result := values[0]
+	for _, value := range values[1:] {
+		result = max(result, value)
+	}
+	return result
+}
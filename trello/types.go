@@ -33,6 +33,14 @@ type Board struct {
 	EnterpriseOwned   bool         `json:"enterpriseOwned"`
 }
 
+// BoardSnapshot is a board with its open lists, open cards, labels and checklists from a single request
+type BoardSnapshot struct {
+	Board
+	Lists      []List      `json:"lists"`
+	Cards      []Card      `json:"cards"`
+	Checklists []Checklist `json:"checklists"`
+}
+
 type DescData struct {
 	Emoji map[string]any `json:"emoji,omitempty"`
 }
@@ -396,3 +404,53 @@ type DeleteCardLabelParams struct {
 	Name    *string `json:"name,omitempty"`
 	LabelID string  `json:"value"`
 }
+
+// ========== CHECKLIST ==========
+
+type Checklist struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	IdBoard    string      `json:"idBoard"`
+	IdCard     string      `json:"idCard"`
+	Pos        float64     `json:"pos"`
+	CheckItems []CheckItem `json:"checkItems"`
+}
+
+type CheckItem struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	IdChecklist string  `json:"idChecklist"`
+	State       string  `json:"state"` // Valid values: complete, incomplete
+	Pos         float64 `json:"pos"`
+	Due         *string `json:"due"`
+	IdMember    *string `json:"idMember"`
+}
+
+type CreateChecklistParams struct {
+	IdCard            string  `json:"idCard"`                      // Required. The ID of the Card that the checklist should be added to. Pattern: ^[0-9a-fA-F]{24}$
+	Name              *string `json:"name,omitempty"`              // The name of the checklist. 1 to 16384 characters long
+	Pos               *string `json:"pos,omitempty"`               // The position of the checklist on the card. Valid values: top, bottom, or a positive number
+	IdChecklistSource *string `json:"idChecklistSource,omitempty"` // The ID of a checklist to copy into the new checklist. Pattern: ^[0-9a-fA-F]{24}$
+}
+
+type UpdateChecklistParams struct {
+	ID   string  `json:"id"`             // Required. ID of a checklist
+	Name *string `json:"name,omitempty"` // Name of the new checklist being created. 1 to 16384 characters long
+	Pos  *string `json:"pos,omitempty"`  // Determines the position of the checklist on the card. Valid values: top, bottom, or a positive number
+}
+
+type CreateCheckItemParams struct {
+	ID      string  `json:"id"`                // Required. ID of the checklist the check item is added to
+	Name    string  `json:"name"`              // Required. The name of the new check item on the checklist. 1 to 16384 characters long
+	Pos     *string `json:"pos,omitempty"`     // The position of the check item in the checklist. Valid values: top, bottom, or a positive number
+	Checked *bool   `json:"checked,omitempty"` // Determines whether the check item is already checked when created. Default: false
+}
+
+type UpdateCheckItemParams struct {
+	ID          string  `json:"id"`                    // Required. The ID of the check item
+	CardID      string  `json:"-"`                     // Required. The ID of the card the check item belongs to, only used in the request path
+	Name        *string `json:"name,omitempty"`        // The new name for the check item
+	State       *string `json:"state,omitempty"`       // One of: complete, incomplete
+	IdChecklist *string `json:"idChecklist,omitempty"` // The ID of the checklist this item is in. Pattern: ^[0-9a-fA-F]{24}$
+	Pos         *string `json:"pos,omitempty"`         // top, bottom, or a positive float
+}